@@ -0,0 +1,144 @@
+package main
+
+import (
+	"aura/src/ast"
+	e "aura/src/evaluator"
+	l "aura/src/lexer"
+	obj "aura/src/object"
+	p "aura/src/parser"
+	"aura/src/repl"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+const usage = `uso:
+	aura [--repl]
+	aura run [opciones] archivo.aura [argumentos...]
+
+opciones:
+`
+
+// represents the options of the run command
+type runOptions struct {
+	check          bool          // represents if the file is only parsed
+	format         bool          // represents if the formatted source is printed instead of running it
+	network        bool          // represents if the scripts can use the network builtins
+	networkTimeout time.Duration // represents the timeout of the network requests
+	profile        bool          // represents if the counters of the evaluated nodes are printed after the run
+}
+
+// parse and evaluate the file in the path, return the exit code of the process
+func run(path string, args []string, options runOptions, stdout, stderr io.Writer) int {
+	if err := e.ValidatePath(path); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "no se pudo leer el archivo %s\n", path)
+		return 1
+	}
+
+	parser := p.NewParser(l.NewLexer(string(source)))
+	program := parser.ParseProgam()
+	if len(parser.Errors()) > 0 {
+		for _, err := range parser.Errors() {
+			fmt.Fprintf(stderr, "%s: %s\n", path, err)
+		}
+		return 1
+	}
+
+	if options.check {
+		return 0
+	}
+
+	if options.format {
+		fmt.Fprint(stdout, ast.Format(program))
+		return 0
+	}
+
+	env := obj.NewEnviroment(nil)
+	env.SetStdout(stdout)
+	env.SetStderr(stderr)
+	env.SetArgs(append([]string{path}, args...))
+	if options.network {
+		env.AllowNetwork(options.networkTimeout)
+	}
+
+	if options.profile {
+		env.EnableProfile()
+		defer printProfile(env.Profile(), env.Stderr())
+	}
+
+	evaluated := e.Run(program, env)
+	if err, isErr := evaluated.(*obj.Error); isErr && !err.Handled {
+		fmt.Fprintln(env.Stderr(), e.ErrorContext(err, string(source)))
+		return 1
+	}
+
+	if evaluated != nil && evaluated != obj.SingletonNUll {
+		fmt.Fprintln(stdout, evaluated.Inspect())
+	}
+
+	return 0
+}
+
+// print the counters of the evaluated nodes sorted from the most evaluated
+func printProfile(profile *obj.Profile, out io.Writer) {
+	counts := profile.Counts()
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] == counts[names[j]] {
+			return names[i] < names[j]
+		}
+		return counts[names[i]] > counts[names[j]]
+	})
+
+	for _, name := range names {
+		fmt.Fprintf(out, "%s: %d\n", name, counts[name])
+	}
+}
+
+func main() {
+	flags := flag.NewFlagSet("aura", flag.ExitOnError)
+	startRepl := flags.Bool("repl", false, "inicia el interprete interactivo")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+
+	var options runOptions
+	flags.BoolVar(&options.check, "check", false, "solo analiza el archivo sin ejecutarlo")
+	flags.BoolVar(&options.format, "format", false, "imprime el codigo fuente formateado, los comentarios no se conservan")
+	flags.BoolVar(&options.network, "red", false, "permite el acceso a la red")
+	flags.BoolVar(&options.profile, "perfil", false, "imprime cuantas veces se evaluo cada tipo de nodo")
+	flags.DurationVar(&options.networkTimeout, "red-timeout", 30*time.Second, "tiempo maximo de las peticiones de red")
+
+	args := os.Args[1:]
+	command := ""
+	if len(args) > 0 && args[0] == "run" {
+		command, args = args[0], args[1:]
+	}
+
+	flags.Parse(args)
+	if *startRepl || (command == "" && flags.NArg() == 0) {
+		repl.StartRpl()
+		return
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	os.Exit(run(flags.Arg(0), flags.Args()[1:], options, os.Stdout, os.Stderr))
+}
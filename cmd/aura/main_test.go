@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, source string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+		return path
+	}
+
+	valid := write("valido.aura", "funcion suma(a,b) { regresa a+b }\nescribir(suma(1, 2))\nsuma(2, 3)")
+	withArgs := write("argumentos.aura", "argumentos()")
+	syntaxError := write("sintaxis.aura", "var = 5;")
+	runtimeError := write("ejecucion.aura", "x := 1;\ny + 1;")
+	notAura := write("texto.txt", "escribir(1)")
+
+	tests := []struct {
+		name     string
+		path     string
+		args     []string
+		options  runOptions
+		code     int
+		stdout   string
+		stderr   string
+		contains bool
+	}{
+		{"ejecuta el archivo", valid, nil, runOptions{}, 0, "3\n5\n", "", false},
+		{"recibe los argumentos", withArgs, []string{"a", "b"}, runOptions{}, 0, "[" + withArgs + ", a, b]\n", "", false},
+		{"solo analiza el archivo", valid, nil, runOptions{check: true}, 0, "", "", false},
+		{"imprime el archivo formateado", valid, nil, runOptions{format: true}, 0, "funcion suma(a, b) {\n    regresa a + b;\n}\n\nescribir(suma(1, 2));\nsuma(2, 3);\n", "", false},
+		{"error de sintaxis", syntaxError, nil, runOptions{}, 1, "", syntaxError + ": se esperaba que el siguient token fuera", true},
+		{"error de sintaxis al formatear", syntaxError, nil, runOptions{format: true}, 1, "", syntaxError + ":", true},
+		{"error al ejecutar", runtimeError, nil, runOptions{}, 1, "", "Identificador no encontrado: y\n  en linea 2, columna 1", true},
+		{"la ruta no existe", filepath.Join(dir, "no_existe.aura"), nil, runOptions{}, 1, "", "la ruta " + filepath.Join(dir, "no_existe.aura") + " no existe\n", false},
+		{"la ruta es un directorio", dir, nil, runOptions{}, 1, "", "la ruta indicada no contiene un archivo: " + dir + "\n", false},
+		{"el archivo no es aura", notAura, nil, runOptions{}, 1, "", "el archivo texto.txt no es una archivo aura valido\n", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(test.path, test.args, test.options, &stdout, &stderr)
+
+			assert.Equal(t, test.code, code)
+			assert.Equal(t, test.stdout, stdout.String())
+			if test.contains {
+				assert.Contains(t, stderr.String(), test.stderr)
+			} else {
+				assert.Equal(t, test.stderr, stderr.String())
+			}
+		})
+	}
+}
@@ -8,31 +8,8 @@ import (
 	"aura/src/repl"
 	"fmt"
 	"os"
-	"path/filepath"
 )
 
-// validate that given path exists, have a file and the extension
-// is .aura
-func validatePath(path string) error {
-	fileInfo, err := os.Stat(path)
-	if err != nil {
-		return fmt.Errorf("la ruta %s no existe", path)
-	}
-
-	if fileInfo.IsDir() {
-		return fmt.Errorf("la ruta indicada no contiene un archivo: %s", path)
-	}
-
-	if filepath.Ext(path) != ".aura" {
-		return fmt.Errorf(
-			"el archivo %s no es una archivo aura valido",
-			filepath.Base(path),
-		)
-	}
-
-	return nil
-}
-
 // read the file in the path and evaluate the file
 func ReadFile(path string) {
 	defer func() {
@@ -57,6 +34,8 @@ func ReadFile(path string) {
 	lexer := l.NewLexer(string(source))
 	parser := p.NewParser(lexer)
 	env := obj.NewEnviroment(nil)
+	// the script can read the file path and the arguments after it
+	env.SetArgs(os.Args[1:])
 	program := parser.ParseProgam()
 
 	if len(parser.Errors()) > 0 {
@@ -67,7 +46,12 @@ func ReadFile(path string) {
 		return
 	}
 
-	evaluated := e.Evaluate(program, env)
+	evaluated := e.Run(program, env)
+	if err, isErr := evaluated.(*obj.Error); isErr && !err.Handled {
+		fmt.Println(e.ErrorContext(err, string(source)))
+		return
+	}
+
 	if evaluated != nil && evaluated != obj.SingletonNUll {
 		fmt.Println(evaluated.Inspect())
 	}
@@ -80,7 +64,7 @@ func main() {
 	}
 
 	filePath := os.Args[1]
-	if err := validatePath(filePath); err != nil {
+	if err := e.ValidatePath(filePath); err != nil {
 		fmt.Println(err.Error())
 		return
 	}
@@ -3,6 +3,7 @@ package ast
 import (
 	l "aura/src/lexer"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -24,6 +25,29 @@ type Expression interface {
 	expressNode() // method to distinguish statements and expressions
 }
 
+// placeholder used by Str when a child node is missing after a syntax error
+const missingNode = "<error>"
+
+// return the string representation of the node or a placeholder if the node
+// is nil, so a partially parsed program can always be printed
+func nodeStr(node ASTNode) string {
+	if isMissing(node) {
+		return missingNode
+	}
+
+	return node.Str()
+}
+
+// check if the node is nil, the nil pointers inside the interface included
+func isMissing(node ASTNode) bool {
+	if node == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(node)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
 // BaseNode is a struct wich all the expressions and statements
 // whill inherit providing the default TokenLiteral implementation
 type BaseNode struct {
@@ -35,6 +59,15 @@ func (b BaseNode) TokenLiteral() string {
 	return b.Token.Literal
 }
 
+// return the line and column where the node starts, zero if is unknown
+func (b BaseNode) Position() (int, int) {
+	if b.Token == nil {
+		return 0, 0
+	}
+
+	return b.Token.Line, b.Token.Column
+}
+
 // Program represents all the program
 type Program struct {
 	Staments []Stmt // represents all the statements in the program
@@ -57,9 +90,9 @@ func (p Program) Str() string {
 	var buf strings.Builder
 	for idx, v := range p.Staments {
 		if idx >= len(p.Staments)-1 {
-			buf.WriteString(v.Str())
+			buf.WriteString(nodeStr(v))
 		} else {
-			buf.WriteString(v.Str() + " ")
+			buf.WriteString(nodeStr(v) + " ")
 		}
 	}
 
@@ -85,7 +118,29 @@ func NewLetStatement(token *l.Token, name *Identifier, value Expression) *LetSta
 func (l LetStatement) stmtNode() {}
 
 func (l LetStatement) Str() string {
-	return fmt.Sprintf("var %s = %s;", l.Name.Str(), l.Value.Str())
+	return fmt.Sprintf("var %s = %s;", nodeStr(l.Name), nodeStr(l.Value))
+}
+
+// Represents a constant declaration, the variable can not be reassigned
+type ConstStatement struct {
+	BaseNode             // represent the token of the statement
+	Name     *Identifier // represents the name of the constant
+	Value    Expression  // represents the value assing to the constant
+}
+
+// generate a new const stament instance
+func NewConstStatement(token *l.Token, name *Identifier, value Expression) *ConstStatement {
+	return &ConstStatement{
+		BaseNode: BaseNode{token},
+		Name:     name,
+		Value:    value,
+	}
+}
+
+func (c ConstStatement) stmtNode() {}
+
+func (c ConstStatement) Str() string {
+	return fmt.Sprintf("constante %s = %s;", nodeStr(c.Name), nodeStr(c.Value))
 }
 
 // Represents a return statement
@@ -102,7 +157,7 @@ func NewReturnStatement(token *l.Token, returnValue Expression) *ReturnStament {
 func (r ReturnStament) stmtNode() {}
 
 func (r ReturnStament) Str() string {
-	return fmt.Sprintf("return %s;", r.ReturnValue.Str())
+	return fmt.Sprintf("return %s;", nodeStr(r.ReturnValue))
 }
 
 // handle expressions statements
@@ -118,7 +173,7 @@ func NewExpressionStament(token *l.Token, expression Expression) *ExpressionStam
 
 func (e ExpressionStament) stmtNode() {}
 func (e ExpressionStament) Str() string {
-	return e.Expression.Str()
+	return nodeStr(e.Expression)
 }
 
 // Suffix representrs a suffix expression
@@ -136,7 +191,7 @@ func NewSuffix(token *l.Token, left Expression, operator string) *Suffix {
 func (s *Suffix) expressNode() {}
 
 func (s *Suffix) Str() string {
-	return fmt.Sprintf("%s%s", s.Left.Str(), s.Operator)
+	return fmt.Sprintf("%s%s", nodeStr(s.Left), s.Operator)
 }
 
 // Represents a block of code delimited by curly braces
@@ -156,9 +211,9 @@ func (b Block) Str() string {
 	var buf strings.Builder
 	for idx, stament := range b.Staments {
 		if idx >= len(b.Staments)-1 {
-			buf.WriteString(stament.Str())
+			buf.WriteString(nodeStr(stament))
 		} else {
-			buf.WriteString(stament.Str() + " ")
+			buf.WriteString(nodeStr(stament) + " ")
 		}
 	}
 
@@ -170,11 +225,11 @@ type If struct {
 	BaseNode               // Extends base node struct
 	Condition   Expression // represents the condition of the expression
 	Consequence *Block     // represents the consequence if the condition is truthy
-	Alternative *Block     // represents the alternative if the condition is not truthy
+	Alternative ASTNode    // represents the alternative if the condition is not truthy, a block or another if
 }
 
 // generates a new if instance
-func NewIf(token *l.Token, condition Expression, consequence, alternative *Block) *If {
+func NewIf(token *l.Token, condition Expression, consequence *Block, alternative ASTNode) *If {
 	return &If{
 		BaseNode:    BaseNode{token},
 		Condition:   condition,
@@ -187,9 +242,9 @@ func (i If) expressNode() {}
 
 func (i If) Str() string {
 	var out strings.Builder
-	out.WriteString(fmt.Sprintf("si %s %s ", i.Condition.Str(), i.Consequence.Str()))
+	out.WriteString(fmt.Sprintf("si %s %s ", nodeStr(i.Condition), nodeStr(i.Consequence)))
 	if i.Alternative != nil {
-		out.WriteString(fmt.Sprintf("si_no %s", i.Alternative.Str()))
+		out.WriteString(fmt.Sprintf("si_no %s", nodeStr(i.Alternative)))
 	}
 
 	return out.String()
@@ -219,20 +274,26 @@ func (f Function) Str() string {
 	var buf strings.Builder
 	for idx, parameter := range f.Parameters {
 		if idx == len(f.Parameters)-1 {
-			buf.WriteString(parameter.Str())
+			buf.WriteString(nodeStr(parameter))
 		} else {
-			buf.WriteString(parameter.Str() + " ")
+			buf.WriteString(nodeStr(parameter) + " ")
 		}
 	}
 
-	return fmt.Sprintf("funcion %s(%s) %s", f.Name.Value, buf.String(), f.Body.Str())
+	name := ""
+	if f.Name != nil {
+		name = f.Name.Value
+	}
+
+	return fmt.Sprintf("funcion %s(%s) %s", name, buf.String(), nodeStr(f.Body))
 }
 
 // represents a function call
 type Call struct {
-	BaseNode               // extends base node struct
-	Function  Expression   // represents the function to be call
-	Arguments []Expression // represents the arguments given to call the function
+	BaseNode                   // extends base node struct
+	Function  Expression       // represents the function to be call
+	Arguments []Expression     // represents the arguments given to call the function
+	Named     []*NamedArgument // represents the arguments given by name after the positional ones
 }
 
 // generates a new Call instance
@@ -250,13 +311,43 @@ func (c Call) Str() string {
 	var buf strings.Builder
 	for idx, arg := range c.Arguments {
 		if idx == len(c.Arguments)-1 {
-			buf.WriteString(arg.Str())
+			buf.WriteString(nodeStr(arg))
 		} else {
-			buf.WriteString(arg.Str() + ", ")
+			buf.WriteString(nodeStr(arg) + ", ")
+		}
+	}
+
+	for idx, arg := range c.Named {
+		if idx > 0 || len(c.Arguments) > 0 {
+			buf.WriteString(", ")
 		}
+		buf.WriteString(nodeStr(arg))
 	}
 
-	return fmt.Sprintf("%s(%s)", c.Function.Str(), buf.String())
+	return fmt.Sprintf("%s(%s)", nodeStr(c.Function), buf.String())
+}
+
+// represents an argument given by name in a function call like:
+//		dibujar(x = 1, y = 2)
+type NamedArgument struct {
+	BaseNode             // extends base node struct
+	Name     *Identifier // represents the name of the parameter
+	Value    Expression  // represents the value given to the parameter
+}
+
+// generates a new NamedArgument instance
+func NewNamedArgument(token *l.Token, name *Identifier, value Expression) *NamedArgument {
+	return &NamedArgument{
+		BaseNode: BaseNode{token},
+		Name:     name,
+		Value:    value,
+	}
+}
+
+func (n *NamedArgument) expressNode() {}
+
+func (n *NamedArgument) Str() string {
+	return fmt.Sprintf("%s = %s", nodeStr(n.Name), nodeStr(n.Value))
 }
 
 // Represents a for expression
@@ -274,7 +365,47 @@ func NewFor(token *l.Token, condition Expression, body *Block) *For {
 func (f *For) expressNode() {}
 
 func (f *For) Str() string {
-	return fmt.Sprintf("por (%s) { %s }", f.Condition.Str(), f.Body.Str())
+	return fmt.Sprintf("por (%s) { %s }", nodeStr(f.Condition), nodeStr(f.Body))
+}
+
+// Represents a classic for expression like:
+//		por (var i = 0; i < 10; i++) { ... }
+type ClassicFor struct {
+	BaseNode             // Extends base node struct
+	Init      Stmt       // represents the optional statement evaluated before the loop
+	Condition Expression // represents the optional condition checked before each iteration
+	Post      Expression // represents the optional expression evaluated after each iteration
+	Body      *Block     // represents the body of the forloop
+}
+
+// generates a new classic for instance
+func NewClassicFor(token *l.Token, init Stmt, condition, post Expression, body *Block) *ClassicFor {
+	return &ClassicFor{
+		BaseNode:  BaseNode{token},
+		Init:      init,
+		Condition: condition,
+		Post:      post,
+		Body:      body,
+	}
+}
+
+func (f *ClassicFor) expressNode() {}
+
+func (f *ClassicFor) Str() string {
+	var init, condition, post string
+	if f.Init != nil {
+		init = strings.TrimSuffix(nodeStr(f.Init), ";")
+	}
+
+	if f.Condition != nil {
+		condition = nodeStr(f.Condition)
+	}
+
+	if f.Post != nil {
+		post = nodeStr(f.Post)
+	}
+
+	return fmt.Sprintf("por (%s; %s; %s) { %s }", init, condition, post, nodeStr(f.Body))
 }
 
 // Represents a WhileLoop expression
@@ -292,7 +423,7 @@ func NewWhile(token *l.Token, cond Expression, body *Block) *While {
 func (w *While) expressNode() {}
 
 func (w *While) Str() string {
-	return fmt.Sprintf("%s %s { %s }", w.TokenLiteral(), w.Condition.Str(), w.Body.Str())
+	return fmt.Sprintf("%s (%s) { %s }", w.TokenLiteral(), nodeStr(w.Condition), nodeStr(w.Body))
 }
 
 // represents an Array Expression
@@ -312,15 +443,44 @@ func (a *Array) Str() string {
 	var buf strings.Builder
 	for idx, val := range a.Values {
 		if idx == len(a.Values)-1 {
-			buf.WriteString(val.Str())
+			buf.WriteString(nodeStr(val))
 		} else {
-			buf.WriteString(val.Str() + ", ")
+			buf.WriteString(nodeStr(val) + ", ")
 		}
 	}
 
 	return buf.String()
 }
 
+// represents a list comprehension like:
+//		lista[x * 2 por (x en valores) si (x > 0)]
+type ListComprehension struct {
+	BaseNode             // Extends base node struct
+	Element   Expression // represents the value added to the list in each iteration
+	Iteration Expression // represents the range expression to iterate
+	Condition Expression // represents the optional condition to filter the values
+}
+
+// generates a new list comprehension instance
+func NewListComprehension(token *l.Token, element, iteration, condition Expression) *ListComprehension {
+	return &ListComprehension{
+		BaseNode:  BaseNode{token},
+		Element:   element,
+		Iteration: iteration,
+		Condition: condition,
+	}
+}
+
+func (lc *ListComprehension) expressNode() {}
+
+func (lc *ListComprehension) Str() string {
+	if lc.Condition != nil {
+		return fmt.Sprintf("lista[%s por (%s) si (%s)]", nodeStr(lc.Element), nodeStr(lc.Iteration), nodeStr(lc.Condition))
+	}
+
+	return fmt.Sprintf("lista[%s por (%s)]", nodeStr(lc.Element), nodeStr(lc.Iteration))
+}
+
 // represents a call to a data structure like maps, arrays or strings
 type CallList struct {
 	BaseNode             // Extends base node struct
@@ -339,7 +499,7 @@ func NewCallList(token *l.Token, listIdent Expression, index Expression) *CallLi
 
 func (c *CallList) expressNode() {}
 func (c *CallList) Str() string {
-	return fmt.Sprintf("%s[%s]", c.ListIdent.Str(), c.Index.Str())
+	return fmt.Sprintf("%s[%s]", nodeStr(c.ListIdent), nodeStr(c.Index))
 }
 
 // Represents a HashMap expression
@@ -358,15 +518,44 @@ func (m *MapExpression) Str() string {
 	var buf strings.Builder
 	for idx, keyVal := range m.Body {
 		if idx >= len(m.Body)-1 {
-			buf.WriteString(keyVal.Str())
+			buf.WriteString(nodeStr(keyVal))
 		} else {
-			buf.WriteString(keyVal.Str() + ", ")
+			buf.WriteString(nodeStr(keyVal) + ", ")
 		}
 	}
 
 	return fmt.Sprintf("mapa{%s}", buf.String())
 }
 
+// represents a map comprehension like:
+//		mapa{x => x * 2 por (x en valores) si (x > 0)}
+type MapComprehension struct {
+	BaseNode             // Extends base node struct
+	Pair      *KeyValue  // represents the key value pair added to the map in each iteration
+	Iteration Expression // represents the range expression to iterate
+	Condition Expression // represents the optional condition to filter the values
+}
+
+// generates a new map comprehension instance
+func NewMapComprehension(token *l.Token, pair *KeyValue, iteration, condition Expression) *MapComprehension {
+	return &MapComprehension{
+		BaseNode:  BaseNode{token},
+		Pair:      pair,
+		Iteration: iteration,
+		Condition: condition,
+	}
+}
+
+func (mc *MapComprehension) expressNode() {}
+
+func (mc *MapComprehension) Str() string {
+	if mc.Condition != nil {
+		return fmt.Sprintf("mapa{%s por (%s) si (%s)}", nodeStr(mc.Pair), nodeStr(mc.Iteration), nodeStr(mc.Condition))
+	}
+
+	return fmt.Sprintf("mapa{%s por (%s)}", nodeStr(mc.Pair), nodeStr(mc.Iteration))
+}
+
 // Represents a class statement
 type ClassStatement struct {
 	BaseNode                   // extends base node
@@ -391,24 +580,24 @@ func (c *ClassStatement) Str() string {
 	var paramsBuf strings.Builder
 	for idx, param := range c.Params {
 		if idx == len(c.Params)-1 {
-			paramsBuf.WriteString(param.Str())
+			paramsBuf.WriteString(nodeStr(param))
 		} else {
-			paramsBuf.WriteString(param.Str() + ", ")
+			paramsBuf.WriteString(nodeStr(param) + ", ")
 		}
 	}
 
 	var buf strings.Builder
 	for idx, method := range c.Methods {
 		if idx == len(c.Methods)-1 {
-			buf.WriteString(method.Str())
+			buf.WriteString(nodeStr(method))
 		} else {
-			buf.WriteString(method.Str() + "\n ")
+			buf.WriteString(nodeStr(method) + "\n ")
 		}
 	}
 
 	return fmt.Sprintf(
 		"clase %s(%s) {\n %s \n }",
-		c.Name.Value,
+		nodeStr(c.Name),
 		paramsBuf.String(),
 		buf.String(),
 	)
@@ -427,7 +616,7 @@ func NewImportStatement(token *l.Token, path Expression) *ImportStatement {
 
 func (i *ImportStatement) stmtNode() {}
 func (i *ImportStatement) Str() string {
-	return fmt.Sprintf("importar %s", i.Path.Str())
+	return fmt.Sprintf("importar %s", nodeStr(i.Path))
 }
 
 // represents an arrow function expression
@@ -451,12 +640,12 @@ func (a *ArrowFunc) Str() string {
 	var buf strings.Builder
 	for idx, param := range a.Params {
 		if idx == len(a.Params)-1 {
-			buf.WriteString(param.Str())
+			buf.WriteString(nodeStr(param))
 		} else {
-			buf.WriteString(param.Str() + ", ")
+			buf.WriteString(nodeStr(param) + ", ")
 		}
 	}
-	return fmt.Sprintf("|%s| => {\n%s\n}", buf.String(), a.Body.Str())
+	return fmt.Sprintf("|%s| => {\n%s\n}", buf.String(), nodeStr(a.Body))
 }
 
 // represents a try catch expression
@@ -481,10 +670,27 @@ func (t *TryExp) expressNode() {}
 func (t TryExp) Str() string {
 	return fmt.Sprintf(
 		"intentar { %s } excepto(%s) { %s }",
-		t.Try.Str(), t.Param.Str(), t.Catch.Str(),
+		nodeStr(t.Try), nodeStr(t.Param), nodeStr(t.Catch),
 	)
 }
 
+// represents the expression form of try like intentar(entero(x)), evaluates
+// to the pair [valor, nulo] or [nulo, error]
+type TryValue struct {
+	BaseNode            // extends base node
+	Value    Expression // represents the expression that can fail
+}
+
+// generates a new try value instance
+func NewTryValue(token *l.Token, value Expression) *TryValue {
+	return &TryValue{BaseNode: BaseNode{token}, Value: value}
+}
+
+func (t *TryValue) expressNode() {}
+func (t *TryValue) Str() string {
+	return fmt.Sprintf("intentar(%s)", nodeStr(t.Value))
+}
+
 // represents a break statement
 type BreakStatement struct {
 	BaseNode
@@ -514,3 +720,95 @@ func (c *ContinueStatement) stmtNode() {}
 func (c *ContinueStatement) Str() string {
 	return "continuar"
 }
+
+// represents a pattern matching expression like:
+//		coincidir valor {
+//			[a, b] => a + b;
+//			_ => 0;
+//		}
+type Match struct {
+	BaseNode             // extends base node
+	Value    Expression  // represents the value to match against the patterns
+	Arms     []*MatchArm // represents the patterns tried in order
+}
+
+// generates a new match instance
+func NewMatch(token *l.Token, value Expression, arms []*MatchArm) *Match {
+	return &Match{BaseNode: BaseNode{token}, Value: value, Arms: arms}
+}
+
+func (m *Match) expressNode() {}
+func (m *Match) Str() string {
+	arms := make([]string, 0, len(m.Arms))
+	for _, arm := range m.Arms {
+		arms = append(arms, nodeStr(arm))
+	}
+
+	return fmt.Sprintf("coincidir %s { %s }", nodeStr(m.Value), strings.Join(arms, "; "))
+}
+
+// represents a pattern and the body evaluated when the pattern matches like:
+//		[a, b] si (a > b) => a
+type MatchArm struct {
+	BaseNode            // extends base node
+	Pattern  Expression // represents the pattern, identifiers bind the matched values
+	Guard    Expression // represents the optional condition evaluated with the bindings
+	Body     *Block     // represents the body evaluated with the bindings
+}
+
+// generates a new match arm instance
+func NewMatchArm(token *l.Token, pattern, guard Expression, body *Block) *MatchArm {
+	return &MatchArm{BaseNode: BaseNode{token}, Pattern: pattern, Guard: guard, Body: body}
+}
+
+func (m *MatchArm) expressNode() {}
+func (m *MatchArm) Str() string {
+	if m.Guard != nil {
+		return fmt.Sprintf("%s si (%s) => %s", nodeStr(m.Pattern), nodeStr(m.Guard), nodeStr(m.Body))
+	}
+
+	return fmt.Sprintf("%s => %s", nodeStr(m.Pattern), nodeStr(m.Body))
+}
+
+// represents a list pattern like [a, 2, _], only matches lists of the same length
+type ListPattern struct {
+	BaseNode              // extends base node
+	Elements []Expression // represents the pattern of each element
+}
+
+// generates a new list pattern instance
+func NewListPattern(token *l.Token, elements ...Expression) *ListPattern {
+	return &ListPattern{BaseNode: BaseNode{token}, Elements: elements}
+}
+
+func (lp *ListPattern) expressNode() {}
+func (lp *ListPattern) Str() string {
+	elements := make([]string, 0, len(lp.Elements))
+	for _, element := range lp.Elements {
+		elements = append(elements, nodeStr(element))
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+// represents a map pattern like mapa{"nombre" => n}, matches maps with at least
+// the given keys
+type MapPattern struct {
+	BaseNode             // extends base node
+	Pairs    []*KeyValue // represents the keys and the pattern of their values
+}
+
+// generates a new map pattern instance
+func NewMapPattern(token *l.Token, pairs ...*KeyValue) *MapPattern {
+	return &MapPattern{BaseNode: BaseNode{token}, Pairs: pairs}
+}
+
+func (mp *MapPattern) expressNode() {}
+func (mp *MapPattern) Str() string {
+	pairs := make([]string, 0, len(mp.Pairs))
+	for _, pair := range mp.Pairs {
+		pairs = append(pairs, nodeStr(pair))
+	}
+
+	return fmt.Sprintf("mapa{%s}", strings.Join(pairs, ", "))
+}
@@ -0,0 +1,830 @@
+package ast
+
+import (
+	l "aura/src/lexer"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// represents how strong an expression binds to its operands, the values
+// are the same of the precedences in the parser
+const (
+	openPrecedence   = 1  // expressions that take everything after them like x := 5 or funcion() {}
+	prefixPrecedence = 7  // prefix expressions like -x
+	suffixPrecedence = 8  // suffix expressions like x++
+	callPrecedence   = 9  // calls and indexes like f(x) or x[0]
+	atomPrecedence   = 10 // literals, identifiers and members like x.y
+)
+
+// represents the precedence of each infix operator
+var operatorPrecedences = map[string]int{
+	"&&": 2,
+	"||": 2,
+	"==": 3,
+	"!=": 3,
+	"<":  4,
+	"<=": 4,
+	">":  4,
+	">=": 4,
+	"+":  5,
+	"-":  5,
+	"*":  6,
+	"/":  6,
+	"%":  6,
+	"+=": 6,
+	"-=": 6,
+	"*=": 6,
+	"/=": 6,
+	"**": 8,
+}
+
+// represents the precedence of the condition of a ternary if, the question
+// mark binds like a product
+const ternaryPrecedence = 6
+
+// represents the string used to indent the blocks
+const indentation = "    "
+
+// represents the max width of a line, the lists and maps in longer lines are
+// written with one element per line
+const maxLineWidth = 80
+
+// Format return the source code of the program with a consistent style, the
+// result is valid source that is parsed to the same program. the comments
+// are not part of the program so they are not kept
+func Format(program *Program) string {
+	printer := &printer{layout: &layout{forced: make(map[Expression]bool)}}
+	printer.statements(program.Staments, true)
+	return printer.buf.String()
+}
+
+// represents the state of the source printer
+type printer struct {
+	buf    strings.Builder // represents the printed source
+	indent int             // represents the nesting of the current block
+	layout *layout         // represents the layout of the literals shared by all the blocks
+}
+
+// represents how the lists and maps are written
+type layout struct {
+	forced  map[Expression]bool // represents the literals that use one line per element because they did not fit
+	inlined []inlineLiteral     // represents the literals written in one line, in the order they were printed
+}
+
+// represents a list or a map written in one line
+type inlineLiteral struct {
+	node   Expression // represents the literal
+	source string     // represents the source of the literal
+}
+
+// return a printer for the statements nested one level
+func (p *printer) nested() *printer {
+	return &printer{indent: p.indent + 1, layout: p.layout}
+}
+
+// write the statements one per line, the top level statements that use
+// more than one line are separated by a blank line
+func (p *printer) statements(statements []Stmt, topLevel bool) {
+	lines := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		mark := len(p.layout.inlined)
+		line := p.statement(statement)
+		for p.breakLongLine(line, p.layout.inlined[mark:]) {
+			line = p.statement(statement)
+		}
+
+		lines = append(lines, line)
+	}
+
+	for idx, line := range lines {
+		if idx > 0 && withoutSemicolon(statements[idx-1]) && continuesExpression(line) {
+			// without the semicolon the statement would be parsed as part of the
+			// previous one like si (x) {} -1
+			p.buf.WriteString(";")
+		}
+
+		if idx > 0 {
+			p.buf.WriteString("\n")
+			if topLevel && (strings.Contains(lines[idx-1], "\n") || strings.Contains(line, "\n")) {
+				p.buf.WriteString("\n")
+			}
+		}
+
+		p.buf.WriteString(strings.Repeat(indentation, p.indent))
+		p.buf.WriteString(line)
+	}
+
+	if len(lines) > 0 && topLevel {
+		p.buf.WriteString("\n")
+	}
+}
+
+// check if the statement is written without semicolon, the imports and the
+// expressions that end with a block like si (x) { ... }
+func withoutSemicolon(statement Stmt) bool {
+	switch node := statement.(type) {
+	case *ImportStatement:
+		return true
+
+	case *ExpressionStament:
+		return endsWithBlock(node.Expression)
+
+	default:
+		return false
+	}
+}
+
+// check if the expression ends with a block like si (x) { ... }
+func endsWithBlock(expression Expression) bool {
+	switch node := expression.(type) {
+	case *If, *For, *ClassicFor, *While, *TryExp, *Match:
+		return true
+
+	case *Function:
+		return node.Name != nil && !isExpressionBody(node.Body)
+
+	default:
+		return false
+	}
+}
+
+// check if the statement starts with a token that the parser would use to
+// continue the previous expression
+func continuesExpression(line string) bool {
+	return strings.HasPrefix(line, "-") || strings.HasPrefix(line, "(")
+}
+
+// return the source of the statement without indentation in the first line
+func (p *printer) statement(statement Stmt) string {
+	switch node := statement.(type) {
+	case *LetStatement:
+		return fmt.Sprintf("var %s = %s;", node.Name.Value, p.expression(node.Value))
+
+	case *ConstStatement:
+		return fmt.Sprintf("constante %s = %s;", node.Name.Value, p.expression(node.Value))
+
+	case *ReturnStament:
+		return fmt.Sprintf("regresa %s;", p.expression(node.ReturnValue))
+
+	case *ExpressionStament:
+		if endsWithBlock(node.Expression) {
+			return p.expression(node.Expression)
+		}
+		return p.expression(node.Expression) + ";"
+
+	case *ClassStatement:
+		return p.class(node)
+
+	case *ImportStatement:
+		// the import statement does not take a semicolon
+		return fmt.Sprintf("importar %s", p.expression(node.Path))
+
+	case *classMethodStatement:
+		return p.classMethod(node.ClassMethodExp)
+
+	case *BreakStatement:
+		return "romper;"
+
+	case *ContinueStatement:
+		return "continuar;"
+
+	case *Block:
+		return p.block(node)
+
+	default:
+		return nodeStr(statement)
+	}
+}
+
+// return the source of the block with the statements indented one level
+func (p *printer) block(block *Block) string {
+	if isMissing(block) || len(block.Staments) == 0 {
+		return "{}"
+	}
+
+	inner := p.nested()
+	inner.statements(block.Staments, false)
+	return fmt.Sprintf("{\n%s\n%s}", inner.buf.String(), strings.Repeat(indentation, p.indent))
+}
+
+// check if the block is the body of an arrow like funcion(x) => x * 2, the
+// parser does not keep the brace token for them
+func isExpressionBody(block *Block) bool {
+	if isMissing(block) || len(block.Staments) != 1 {
+		return false
+	}
+
+	if _, isExpression := block.Staments[0].(*ExpressionStament); !isExpression {
+		return false
+	}
+
+	return block.Token == nil || block.Token.Literal != "{"
+}
+
+// return the source of the body of a function, the arrow bodies keep the arrow
+func (p *printer) body(block *Block) string {
+	if isExpressionBody(block) {
+		expression := block.Staments[0].(*ExpressionStament).Expression
+		return "=> " + p.expression(expression)
+	}
+
+	return p.block(block)
+}
+
+// return the source of the body of an arrow function, the arrow is written
+// before the blocks too
+func (p *printer) arrowBody(block *Block) string {
+	if isExpressionBody(block) {
+		return p.body(block)
+	}
+
+	return "=> " + p.block(block)
+}
+
+// return the source of the class with one method per line
+func (p *printer) class(class *ClassStatement) string {
+	if len(class.Methods) == 0 {
+		return fmt.Sprintf("clase %s(%s) {}", class.Name.Value, identifiers(class.Params))
+	}
+
+	methods := make([]Stmt, 0, len(class.Methods))
+	for _, method := range class.Methods {
+		methods = append(methods, &classMethodStatement{method})
+	}
+
+	inner := p.nested()
+	inner.statements(methods, true)
+	return fmt.Sprintf(
+		"clase %s(%s) {\n%s%s}",
+		class.Name.Value,
+		identifiers(class.Params),
+		inner.buf.String(),
+		strings.Repeat(indentation, p.indent),
+	)
+}
+
+// represents a class method while the class body is printed, so the methods
+// are separated like the top level statements
+type classMethodStatement struct {
+	*ClassMethodExp
+}
+
+func (c *classMethodStatement) stmtNode() {}
+
+// return the source of the class method, the methods written with an arrow
+// keep the statement after the arrow
+func (p *printer) classMethod(method *ClassMethodExp) string {
+	header := fmt.Sprintf("%s(%s)", method.Name.Value, identifiers(method.Params))
+	if !isMissing(method.Body) && method.Body.Token == nil && len(method.Body.Staments) == 1 {
+		return fmt.Sprintf("%s => %s", header, p.statement(method.Body.Staments[0]))
+	}
+
+	return fmt.Sprintf("%s %s", header, p.block(method.Body))
+}
+
+// return the source of the expression
+func (p *printer) expression(expression Expression) string {
+	if isMissing(expression) {
+		return missingNode
+	}
+
+	switch node := expression.(type) {
+	case *Identifier:
+		return node.Value
+
+	case *Integer:
+		return nodeStr(node)
+
+	case *FloatExp:
+		return formatFloat(node.Value)
+
+	case *Boolean:
+		return formatBoolean(node)
+
+	case *StringLiteral:
+		return quoteString(node.Value)
+
+	case *Char:
+		return quoteChar(node.Value)
+
+	case *InterpolatedString:
+		return p.interpolatedString(node)
+
+	case *NullExpression:
+		return "nulo"
+
+	case *Prefix:
+		return p.prefix(node)
+
+	case *Infix:
+		return p.infix(node)
+
+	case *Suffix:
+		return p.operand(node.Left, suffixPrecedence) + node.Operator
+
+	case *TernaryIf:
+		return fmt.Sprintf(
+			"%s ? %s : %s",
+			p.operand(node.Condition, ternaryPrecedence+1),
+			p.operand(node.Consequence, prefixPrecedence),
+			p.operand(node.Alternative, prefixPrecedence),
+		)
+
+	case *Reassignment:
+		return fmt.Sprintf("%s = %s", p.operand(node.Identifier, callPrecedence), p.expression(node.NewVal))
+
+	case *AssigmentExp:
+		return fmt.Sprintf("%s := %s", node.Name.Value, p.expression(node.Val))
+
+	case *Call:
+		return p.call(node)
+
+	case *NamedArgument:
+		return fmt.Sprintf("%s = %s", node.Name.Value, p.expression(node.Value))
+
+	case *CallList:
+		return fmt.Sprintf("%s[%s]", p.operand(node.ListIdent, callPrecedence), p.expression(node.Index))
+
+	case *MethodExpression:
+		return fmt.Sprintf("%s:%s", p.operand(node.Obj, callPrecedence), p.expression(node.Method))
+
+	case *ClassFieldCall:
+		return fmt.Sprintf("%s.%s", p.operand(node.Class, callPrecedence), p.expression(node.Field))
+
+	case *ClassCall:
+		return fmt.Sprintf("nuevo %s(%s)", node.Class.Value, p.expressions(node.Arguments))
+
+	case *Array:
+		return p.collection(node, "lista[", "]", node.Token, node.Values)
+
+	case *ListComprehension:
+		return fmt.Sprintf("lista[%s %s]", p.expression(node.Element), p.comprehension(node.Iteration, node.Condition))
+
+	case *MapExpression:
+		pairs := make([]Expression, 0, len(node.Body))
+		for _, pair := range node.Body {
+			pairs = append(pairs, pair)
+		}
+		return p.collection(node, "mapa{", "}", node.Token, pairs)
+
+	case *MapComprehension:
+		return fmt.Sprintf("mapa{%s %s}", p.expression(node.Pair), p.comprehension(node.Iteration, node.Condition))
+
+	case *KeyValue:
+		return fmt.Sprintf("%s => %s", p.expression(node.Key), p.expression(node.Value))
+
+	case *RangeExpression:
+		return fmt.Sprintf("%s en %s", p.expression(node.Variable), p.expression(node.Range))
+
+	case *If:
+		return p.ifExpression(node)
+
+	case *Function:
+		return p.function(node)
+
+	case *ArrowFunc:
+		return fmt.Sprintf("|%s| %s", identifiers(node.Params), p.arrowBody(node.Body))
+
+	case *For:
+		return fmt.Sprintf("por (%s) %s", p.expression(node.Condition), p.block(node.Body))
+
+	case *ClassicFor:
+		return p.classicFor(node)
+
+	case *While:
+		return fmt.Sprintf("mientras (%s) %s", p.expression(node.Condition), p.block(node.Body))
+
+	case *TryExp:
+		return p.try(node)
+
+	case *TryValue:
+		return fmt.Sprintf("intentar(%s)", p.expression(node.Value))
+
+	case *ThorwExpression:
+		return fmt.Sprintf("lanzar Error(%s)", p.expression(node.Message))
+
+	case *Match:
+		return p.match(node)
+
+	case *ListPattern:
+		return fmt.Sprintf("[%s]", p.expressions(node.Elements))
+
+	case *MapPattern:
+		return fmt.Sprintf("mapa{%s}", p.keyValues(node.Pairs))
+
+	case *ClassMethodExp:
+		return p.classMethod(node)
+
+	default:
+		return nodeStr(expression)
+	}
+}
+
+// return the source of the expression used as operand, the expression is
+// grouped when it binds weaker than the given precedence
+func (p *printer) operand(expression Expression, precedence int) string {
+	if expressionPrecedence(expression) < precedence {
+		return fmt.Sprintf("(%s)", p.expression(expression))
+	}
+
+	return p.expression(expression)
+}
+
+// return how strong the expression binds to its operands
+func expressionPrecedence(expression Expression) int {
+	switch node := expression.(type) {
+	case *Infix:
+		if precedence, exists := operatorPrecedences[node.Operator]; exists {
+			return precedence
+		}
+		return openPrecedence
+
+	case *Prefix:
+		return prefixPrecedence
+
+	case *Suffix:
+		return suffixPrecedence
+
+	case *Call, *CallList:
+		return callPrecedence
+
+	case *If, *Function, *ArrowFunc, *For, *ClassicFor, *While, *TryExp, *Match,
+		*TernaryIf, *Reassignment, *AssigmentExp, *ThorwExpression:
+		return openPrecedence
+
+	default:
+		return atomPrecedence
+	}
+}
+
+// return the source of the prefix expression
+func (p *printer) prefix(prefix *Prefix) string {
+	operand := p.operand(prefix.Rigth, prefixPrecedence)
+	if strings.HasPrefix(operand, prefix.Operator) {
+		// the operators would be read as another token like --x
+		operand = fmt.Sprintf("(%s)", operand)
+	}
+
+	return prefix.Operator + operand
+}
+
+// return the source of the infix expression, the operators with the same
+// precedence are evaluated from left to right
+func (p *printer) infix(infix *Infix) string {
+	precedence := expressionPrecedence(infix)
+	return fmt.Sprintf(
+		"%s %s %s",
+		p.operand(infix.Left, precedence),
+		infix.Operator,
+		p.operand(infix.Rigth, precedence+1),
+	)
+}
+
+// return the source of the call, a member written as (x.y)() keeps the
+// parenthesis because x.y() is a call inside the member
+func (p *printer) call(call *Call) string {
+	function := p.operand(call.Function, callPrecedence)
+	switch call.Function.(type) {
+	case *ClassFieldCall, *MethodExpression:
+		function = fmt.Sprintf("(%s)", function)
+	}
+
+	arguments := make([]Expression, 0, len(call.Arguments)+len(call.Named))
+	arguments = append(arguments, call.Arguments...)
+	for _, named := range call.Named {
+		arguments = append(arguments, named)
+	}
+
+	return fmt.Sprintf("%s(%s)", function, p.expressions(arguments))
+}
+
+// return the source of a list or a map, the literals that were written in
+// more than one line or do not fit in the line have one element per line and
+// a trailing comma
+func (p *printer) collection(node Expression, open, close string, token *l.Token, elements []Expression) string {
+	inline := fmt.Sprintf("%s%s%s", open, p.expressions(elements), close)
+	if len(elements) == 0 || (!p.layout.forced[node] && !writtenMultiline(token, elements)) {
+		p.layout.inlined = append(p.layout.inlined, inlineLiteral{node, inline})
+		return inline
+	}
+
+	inner := p.nested()
+	var buf strings.Builder
+	buf.WriteString(open + "\n")
+	for _, element := range elements {
+		buf.WriteString(strings.Repeat(indentation, inner.indent))
+		buf.WriteString(inner.expression(element) + ",\n")
+	}
+
+	buf.WriteString(strings.Repeat(indentation, p.indent) + close)
+	return buf.String()
+}
+
+// choose the longest literal written in a line that is too long to be written
+// with one element per line, return false if there is none
+func (p *printer) breakLongLine(source string, inlined []inlineLiteral) bool {
+	longLines := make([]string, 0)
+	for _, line := range strings.Split(strings.Repeat(indentation, p.indent)+source, "\n") {
+		if utf8.RuneCountInString(line) > maxLineWidth {
+			longLines = append(longLines, line)
+		}
+	}
+
+	var longest *inlineLiteral = nil
+	for idx, literal := range inlined {
+		if p.layout.forced[literal.node] || (longest != nil && len(literal.source) <= len(longest.source)) {
+			continue
+		}
+
+		for _, line := range longLines {
+			if strings.Contains(line, literal.source) {
+				longest = &inlined[idx]
+				break
+			}
+		}
+	}
+
+	if longest == nil {
+		return false
+	}
+
+	p.layout.forced[longest.node] = true
+	return true
+}
+
+// check if any of the elements starts in other line than the literal
+func writtenMultiline(token *l.Token, elements []Expression) bool {
+	if token == nil {
+		return false
+	}
+
+	for _, element := range elements {
+		if line := startLine(element); line != 0 && line != token.Line {
+			return true
+		}
+	}
+
+	return false
+}
+
+// return the line where the expression starts, zero if is unknown. the
+// expressions like a + b have the token of the operator so the line of the
+// left operand is used
+func startLine(expression Expression) int {
+	switch node := expression.(type) {
+	case *Infix:
+		return startLine(node.Left)
+
+	case *Suffix:
+		return startLine(node.Left)
+
+	case *TernaryIf:
+		return startLine(node.Condition)
+
+	case *Call:
+		return startLine(node.Function)
+
+	case *CallList:
+		return startLine(node.ListIdent)
+
+	case *MethodExpression:
+		return startLine(node.Obj)
+
+	case *ClassFieldCall:
+		return startLine(node.Class)
+
+	case *Reassignment:
+		return startLine(node.Identifier)
+
+	case *AssigmentExp:
+		return startLine(node.Name)
+
+	case *RangeExpression:
+		return startLine(node.Variable)
+	}
+
+	if isMissing(expression) {
+		return 0
+	}
+
+	if positioned, isPositioned := expression.(interface{ Position() (int, int) }); isPositioned {
+		line, _ := positioned.Position()
+		return line
+	}
+
+	return 0
+}
+
+// return the source of the expressions separated by commas
+func (p *printer) expressions(expressions []Expression) string {
+	values := make([]string, 0, len(expressions))
+	for _, expression := range expressions {
+		values = append(values, p.expression(expression))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// return the source of the key value pairs separated by commas
+func (p *printer) keyValues(pairs []*KeyValue) string {
+	values := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		values = append(values, p.expression(pair))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// return the source of the iteration of a comprehension like por (x en y) si (x > 0)
+func (p *printer) comprehension(iteration, condition Expression) string {
+	if isMissing(condition) {
+		return fmt.Sprintf("por (%s)", p.expression(iteration))
+	}
+
+	return fmt.Sprintf("por (%s) si (%s)", p.expression(iteration), p.expression(condition))
+}
+
+// return the source of the if expression and its alternatives
+func (p *printer) ifExpression(ifExp *If) string {
+	source := fmt.Sprintf("si (%s) %s", p.expression(ifExp.Condition), p.block(ifExp.Consequence))
+	switch alternative := ifExp.Alternative.(type) {
+	case *If:
+		return fmt.Sprintf("%s si_no %s", source, p.ifExpression(alternative))
+
+	case *Block:
+		return fmt.Sprintf("%s si_no %s", source, p.block(alternative))
+
+	default:
+		return source
+	}
+}
+
+// return the source of the function, the functions declared with an arrow
+// keep the arrow
+func (p *printer) function(function *Function) string {
+	name := ""
+	if function.Name != nil {
+		name = " " + function.Name.Value
+	}
+
+	return fmt.Sprintf("funcion%s(%s) %s", name, identifiers(function.Parameters), p.body(function.Body))
+}
+
+// return the source of the classic for, the missing parts are left empty
+func (p *printer) classicFor(classicFor *ClassicFor) string {
+	var header strings.Builder
+	if !isMissing(classicFor.Init) {
+		header.WriteString(strings.TrimSuffix(p.statement(classicFor.Init), ";"))
+	}
+
+	header.WriteString(";")
+	if !isMissing(classicFor.Condition) {
+		header.WriteString(" " + p.expression(classicFor.Condition))
+	}
+
+	header.WriteString(";")
+	if !isMissing(classicFor.Post) {
+		header.WriteString(" " + p.expression(classicFor.Post))
+	}
+
+	return fmt.Sprintf("por (%s) %s", header.String(), p.block(classicFor.Body))
+}
+
+// return the source of the try expression
+func (p *printer) try(try *TryExp) string {
+	return fmt.Sprintf(
+		"intentar %s excepto(%s) %s",
+		p.block(try.Try),
+		try.Param.Value,
+		p.block(try.Catch),
+	)
+}
+
+// return the source of the match with one arm per line
+func (p *printer) match(match *Match) string {
+	if len(match.Arms) == 0 {
+		return fmt.Sprintf("coincidir %s {}", p.expression(match.Value))
+	}
+
+	inner := p.nested()
+	arms := make([]string, 0, len(match.Arms))
+	for _, arm := range match.Arms {
+		arms = append(arms, strings.Repeat(indentation, inner.indent)+inner.matchArm(arm))
+	}
+
+	return fmt.Sprintf(
+		"coincidir %s {\n%s\n%s}",
+		p.expression(match.Value),
+		strings.Join(arms, "\n"),
+		strings.Repeat(indentation, p.indent),
+	)
+}
+
+// return the source of the arm, the arms with an expression end with a
+// semicolon so the next pattern is not parsed as part of the expression
+func (p *printer) matchArm(arm *MatchArm) string {
+	pattern := p.expression(arm.Pattern)
+	if !isMissing(arm.Guard) {
+		pattern = fmt.Sprintf("%s si (%s)", pattern, p.expression(arm.Guard))
+	}
+
+	if isExpressionBody(arm.Body) {
+		return fmt.Sprintf("%s %s;", pattern, p.body(arm.Body))
+	}
+
+	return fmt.Sprintf("%s => %s", pattern, p.block(arm.Body))
+}
+
+// return the source of the interpolated string
+func (p *printer) interpolatedString(interpolated *InterpolatedString) string {
+	var buf strings.Builder
+	buf.WriteString(`"`)
+	for _, part := range interpolated.Parts {
+		if text, isText := part.(*StringLiteral); isText {
+			buf.WriteString(escapeString(text.Value, '"'))
+			continue
+		}
+
+		buf.WriteString(fmt.Sprintf("${%s}", p.expression(part)))
+	}
+
+	buf.WriteString(`"`)
+	return buf.String()
+}
+
+// return the names of the identifiers separated by commas
+func identifiers(identifiers []*Identifier) string {
+	names := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		names = append(names, identifier.Value)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// return the source of the float, it always has a decimal point or an
+// exponent so it is not read as an integer
+func formatFloat(value float64) string {
+	if math.Abs(value) >= 1e21 || (value != 0 && math.Abs(value) < 1e-6) {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	source := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.Contains(source, ".") {
+		source += ".0"
+	}
+
+	return source
+}
+
+// return the keyword of the boolean
+func formatBoolean(boolean *Boolean) string {
+	if boolean.Value != nil && *boolean.Value {
+		return "verdadero"
+	}
+
+	return "falso"
+}
+
+// return the string between double quotes with the escape sequences
+func quoteString(value string) string {
+	return `"` + escapeString(value, '"') + `"`
+}
+
+// return the character between single quotes with the escape sequences
+func quoteChar(value rune) string {
+	return "'" + escapeString(string(value), '\'') + "'"
+}
+
+// replace the characters that can not be written inside the quotes with
+// their escape sequences, the ${ is escaped so it is not interpolated
+func escapeString(value string, quote rune) string {
+	var buf strings.Builder
+	for idx, char := range value {
+		switch {
+		case char == quote || char == '\\':
+			buf.WriteRune('\\')
+			buf.WriteRune(char)
+
+		case char == '\n':
+			buf.WriteString(`\n`)
+
+		case char == '\t':
+			buf.WriteString(`\t`)
+
+		case char == '\r':
+			buf.WriteString(`\r`)
+
+		case char == '$' && strings.HasPrefix(value[idx+1:], "{"):
+			buf.WriteString(`\$`)
+
+		default:
+			buf.WriteRune(char)
+		}
+	}
+
+	return buf.String()
+}
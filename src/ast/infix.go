@@ -25,8 +25,10 @@ func Newinfix(token *l.Token, r Expression, operator string, l Expression) *Infi
 
 func (i Infix) expressNode() {}
 
+// every infix expression is wrapped in parentheses, so the grouping of the
+// source is kept without knowing the precedence of the operators
 func (i Infix) Str() string {
-	return fmt.Sprintf("(%s %s %s)", i.Left.Str(), i.Operator, i.Rigth.Str())
+	return fmt.Sprintf("(%s %s %s)", nodeStr(i.Left), i.Operator, nodeStr(i.Rigth))
 }
 
 // represents an in expression like:
@@ -51,7 +53,7 @@ func NewRange(token *l.Token, variable Expression, Range Expression) *RangeExpre
 func (r *RangeExpression) expressNode() {}
 
 func (r *RangeExpression) Str() string {
-	return fmt.Sprintf("%s en %s", r.Variable.Str(), r.Range.Str())
+	return fmt.Sprintf("%s en %s", nodeStr(r.Variable), nodeStr(r.Range))
 }
 
 // represents a key value expression:
@@ -74,7 +76,7 @@ func NewKeyVal(token *l.Token, key, value Expression) *KeyValue {
 func (k *KeyValue) expressNode() {}
 
 func (k *KeyValue) Str() string {
-	return fmt.Sprintf("%s => %s", k.Key.Str(), k.Value.Str())
+	return fmt.Sprintf("%s => %s", nodeStr(k.Key), nodeStr(k.Value))
 }
 
 // represents a method expression like:
@@ -97,7 +99,7 @@ func NewMethodExpression(token *l.Token, obj Expression, method Expression) *Met
 func (m *MethodExpression) expressNode() {}
 
 func (m *MethodExpression) Str() string {
-	return fmt.Sprintf("%s:%s", m.Obj.Str(), m.Method.Str())
+	return fmt.Sprintf("%s:%s", nodeStr(m.Obj), nodeStr(m.Method))
 }
 
 // represents a reassigment expression
@@ -119,7 +121,7 @@ func NewReassignment(token *l.Token, ident Expression, newVal Expression) *Reass
 func (r *Reassignment) expressNode() {}
 
 func (r *Reassignment) Str() string {
-	return fmt.Sprintf("%s = %s", r.Identifier.Str(), r.NewVal.Str())
+	return fmt.Sprintf("%s = %s", nodeStr(r.Identifier), nodeStr(r.NewVal))
 }
 
 // represents an assigment with the := operator
@@ -140,7 +142,7 @@ func NewAssigmentExp(token *l.Token, name *Identifier, val Expression) *Assigmen
 
 func (a *AssigmentExp) expressNode() {}
 func (a *AssigmentExp) Str() string {
-	return fmt.Sprintf("%s := %s", a.Name.Value, a.Val.Str())
+	return fmt.Sprintf("%s := %s", nodeStr(a.Name), nodeStr(a.Val))
 }
 
 // represents a ternary if expression
@@ -162,5 +164,5 @@ func NewTernaryIf(token *l.Token, condition, consequence, alternative Expression
 
 func (t *TernaryIf) expressNode() {}
 func (t *TernaryIf) Str() string {
-	return fmt.Sprintf("%s ? %s : %s", t.Condition.Str(), t.Condition.Str(), t.Alternative.Str())
+	return fmt.Sprintf("%s ? %s : %s", nodeStr(t.Condition), nodeStr(t.Consequence), nodeStr(t.Alternative))
 }
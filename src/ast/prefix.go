@@ -3,6 +3,7 @@ package ast
 import (
 	l "aura/src/lexer"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -24,8 +25,9 @@ func NewPrefix(token *l.Token, operator string, rigth Expression) *Prefix {
 }
 func (p Prefix) expressNode() {}
 
+// the prefix expression is wrapped in parentheses like the infix expressions
 func (p Prefix) Str() string {
-	return fmt.Sprintf("(%s %s)", p.Operator, p.Rigth.Str())
+	return fmt.Sprintf("(%s %s)", p.Operator, nodeStr(p.Rigth))
 }
 
 // represents a variable or function name
@@ -59,6 +61,10 @@ func NewInteger(token *l.Token, value *int) *Integer {
 func (i Integer) expressNode() {}
 
 func (i Integer) Str() string {
+	if i.Value == nil {
+		return missingNode
+	}
+
 	return fmt.Sprintf("%d", *i.Value)
 }
 
@@ -111,6 +117,51 @@ func (s StringLiteral) Str() string {
 	return s.Value
 }
 
+// represents a character literal like 'a'
+type Char struct {
+	BaseNode      // Extends base node struct
+	Value    rune // represents the value of the character
+}
+
+// return a new character instance
+func NewChar(token *l.Token, value rune) *Char {
+	return &Char{BaseNode: BaseNode{token}, Value: value}
+}
+
+func (c *Char) expressNode() {}
+
+func (c *Char) Str() string {
+	return strconv.QuoteRune(c.Value)
+}
+
+// represents a string with interpolated expressions like:
+//		"hola ${nombre}"
+type InterpolatedString struct {
+	BaseNode              // Extends base node struct
+	Parts    []Expression // represents the texts and the expressions of the string in order
+}
+
+// return a new interpolated string instance
+func NewInterpolatedString(token *l.Token, parts ...Expression) *InterpolatedString {
+	return &InterpolatedString{BaseNode: BaseNode{token}, Parts: parts}
+}
+
+func (s *InterpolatedString) expressNode() {}
+
+func (s *InterpolatedString) Str() string {
+	var buf strings.Builder
+	for _, part := range s.Parts {
+		if text, isText := part.(*StringLiteral); isText {
+			buf.WriteString(strings.ReplaceAll(text.Value, "${", `\${`))
+			continue
+		}
+
+		buf.WriteString(fmt.Sprintf("${%s}", nodeStr(part)))
+	}
+
+	return buf.String()
+}
+
 // represents a null expression
 type NullExpression struct {
 	BaseNode // Extends base node struct
@@ -149,13 +200,13 @@ func (cc *ClassCall) Str() string {
 	var buf strings.Builder
 	for idx, arg := range cc.Arguments {
 		if idx == len(cc.Arguments)-1 {
-			buf.WriteString(arg.Str())
+			buf.WriteString(nodeStr(arg))
 		} else {
-			buf.WriteString(arg.Str() + ", ")
+			buf.WriteString(nodeStr(arg) + ", ")
 		}
 	}
 
-	return fmt.Sprintf("nuevo %s(%s)", cc.Class.Str(), buf.String())
+	return fmt.Sprintf("nuevo %s(%s)", nodeStr(cc.Class), buf.String())
 }
 
 // represents a call to a field or method
@@ -177,7 +228,7 @@ func NewClassFieldCall(token *l.Token, class Expression, field Expression) *Clas
 func (cfc *ClassFieldCall) expressNode() {}
 
 func (cfc *ClassFieldCall) Str() string {
-	return fmt.Sprintf("%s.%s", cfc.Class.Str(), cfc.Field.Str())
+	return fmt.Sprintf("%s.%s", nodeStr(cfc.Class), nodeStr(cfc.Field))
 }
 
 // Represents the class method
@@ -204,13 +255,13 @@ func (cm *ClassMethodExp) Str() string {
 	var buf strings.Builder
 	for idx, param := range cm.Params {
 		if idx == len(cm.Params)-1 {
-			buf.WriteString(param.Str())
+			buf.WriteString(nodeStr(param))
 		} else {
-			buf.WriteString(param.Str() + ", ")
+			buf.WriteString(nodeStr(param) + ", ")
 		}
 	}
 
-	return fmt.Sprintf("%s(%s){%s}", cm.Name.Str(), buf.String(), cm.Body.Str())
+	return fmt.Sprintf("%s(%s){%s}", nodeStr(cm.Name), buf.String(), nodeStr(cm.Body))
 }
 
 // represents an throw exception expression
@@ -227,5 +278,5 @@ func NewThrowExpression(token *l.Token, message Expression) *ThorwExpression {
 func (t *ThorwExpression) expressNode() {}
 
 func (t *ThorwExpression) Str() string {
-	return fmt.Sprintf("lanzar Error(%s)", t.Message.Str())
+	return fmt.Sprintf("lanzar Error(%s)", nodeStr(t.Message))
 }
@@ -4,8 +4,10 @@ import (
 	obj "aura/src/object"
 	"bufio"
 	"fmt"
-	"math"
 	"os"
+	"reflect"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,7 +17,6 @@ import (
 // use singleton pattern for the scanner
 var (
 	scanner = bufio.NewScanner(os.Stdin)
-	writer  = bufio.NewWriter(os.Stdout)
 )
 
 // return an error indicating the the builtin has wrong number of args
@@ -42,30 +43,107 @@ func Longitud(args ...obj.Object) obj.Object {
 	case *obj.String:
 		return &obj.Number{Value: utf8.RuneCountInString(arg.Value)}
 
+	case *obj.Char:
+		return &obj.Number{Value: 1}
+
 	case *obj.List:
 		return &obj.Number{Value: len(arg.Values)}
 
 	case *obj.Map:
 		return &obj.Number{Value: len(arg.Store)}
 
+	case *obj.Set:
+		return &obj.Number{Value: len(arg.Values)}
+
 	default:
 		return unsoportedArgumentType("largo", obj.Types[args[0].Type()])
 	}
 }
 
-// same as println function
-func Escribir(args ...obj.Object) obj.Object {
+// same as println function, the output is the writer of the enviroment
+func Escribir(env *obj.Enviroment, args ...obj.Object) obj.Object {
 	var buff strings.Builder
 	for _, arg := range args {
 		buff.WriteString(arg.Inspect())
 	}
 
-	defer writer.Flush()
-	writer.WriteString(buff.String() + "\n")
+	fmt.Fprintln(env.Stdout(), buff.String())
 	return obj.SingletonNUll
 }
 
-// same as python input function
+// same as escribir but the output is the diagnostics writer of the enviroment
+func printError(env *obj.Enviroment, args ...obj.Object) obj.Object {
+	var buff strings.Builder
+	for _, arg := range args {
+		buff.WriteString(arg.Inspect())
+	}
+
+	fmt.Fprintln(env.Stderr(), buff.String())
+	return obj.SingletonNUll
+}
+
+// return a new channel for the interpreter of the enviroment, the optional
+// argument is the size of the buffer
+func newChannel(env *obj.Enviroment, args ...obj.Object) obj.Object {
+	if len(args) > 1 {
+		return wrongNumberofArgs("canal", len(args), 1)
+	}
+
+	if len(args) == 0 {
+		return obj.NewChannel(0, env)
+	}
+
+	size, isNum := args[0].(*obj.Number)
+	if !isNum {
+		return unsoportedArgumentType("canal", obj.Types[args[0].Type()])
+	}
+
+	if size.Value < 0 {
+		return &obj.Error{Message: fmt.Sprintf("el tamaño del canal no puede ser negativo: %d", size.Value)}
+	}
+
+	return obj.NewChannel(size.Value, env)
+}
+
+// send a value through the channel, waits until other function receives it
+// if the channel has no space. if every function is waiting and no one can
+// receive it the error is returned instead of waiting forever
+func send(args ...obj.Object) obj.Object {
+	if len(args) != 2 {
+		return wrongNumberofArgs("enviar", len(args), 2)
+	}
+
+	channel, isChannel := args[0].(*obj.Channel)
+	if !isChannel {
+		return unsoportedArgumentType("enviar", obj.Types[args[0].Type()])
+	}
+
+	return channel.Send(args[1])
+}
+
+// block until the future returned by girar has a result and return it,
+// without arguments return the esperar method for futures. if the function of
+// the future is waiting for the caller the error is returned
+func wait(args ...obj.Object) obj.Object {
+	if len(args) > 1 {
+		return wrongNumberofArgs("esperar", len(args), 1)
+	}
+
+	if len(args) == 0 {
+		return obj.NewMethod(obj.SingletonNUll, obj.WAIT)
+	}
+
+	future, isFuture := args[0].(*obj.Future)
+	if !isFuture {
+		return unsoportedArgumentType("esperar", obj.Types[args[0].Type()])
+	}
+
+	return future.Wait()
+}
+
+// same as python input function. with a channel waits until other function
+// sends a value, if every function is waiting and no one can send it the error
+// is returned instead of waiting forever
 func Recibir(args ...obj.Object) obj.Object {
 	if len(args) > 1 {
 		return wrongNumberofArgs("recibir", len(args), 1)
@@ -82,6 +160,10 @@ func Recibir(args ...obj.Object) obj.Object {
 		return &obj.String{Value: str}
 	}
 
+	if channel, isChannel := args[0].(*obj.Channel); isChannel {
+		return channel.Receive()
+	}
+
 	return unsoportedArgumentType("recibir", obj.Types[args[0].Type()])
 }
 
@@ -154,7 +236,7 @@ func formatrArgs(args ...obj.Object) obj.Object {
 	return &obj.String{Value: formated}
 }
 
-func printF(args ...obj.Object) obj.Object {
+func printF(env *obj.Enviroment, args ...obj.Object) obj.Object {
 	if len(args) <= 1 {
 		return wrongNumberofArgs("format", 0, 100)
 	}
@@ -167,8 +249,7 @@ func printF(args ...obj.Object) obj.Object {
 	}
 
 	formated := formatString(str.Value, args[1:])
-	defer writer.Flush()
-	writer.WriteString(formated + "\n")
+	fmt.Fprintln(env.Stdout(), formated)
 	return obj.SingletonNUll
 }
 
@@ -189,23 +270,23 @@ func rango(args ...obj.Object) obj.Object {
 	}
 }
 
+// pause the execution the given milliseconds
 func slep(args ...obj.Object) obj.Object {
-	if len(args) > 1 {
+	if len(args) != 1 {
 		return wrongNumberofArgs("dormir", len(args), 1)
 	}
 
-	switch arg := args[0].(type) {
-	case *obj.Number:
-		time.Sleep(time.Duration(arg.Value * int(time.Second)))
-		return obj.SingletonNUll
-
-	case *obj.Float:
-		time.Sleep(time.Duration(arg.Value * float64(time.Second)))
-		return obj.SingletonNUll
+	milliseconds, isNum := args[0].(*obj.Number)
+	if !isNum {
+		return unsoportedArgumentType("dormir", obj.Types[args[0].Type()])
+	}
 
-	default:
-		return unsoportedArgumentType("dormir", obj.Types[arg.Type()])
+	if milliseconds.Value < 0 {
+		return &obj.Error{Message: fmt.Sprintf("dormir no puede recibir milisegundos negativos: %d", milliseconds.Value)}
 	}
+
+	time.Sleep(time.Duration(milliseconds.Value) * time.Millisecond)
+	return obj.SingletonNUll
 }
 
 // return the type of the object
@@ -217,6 +298,88 @@ func Tipo(args ...obj.Object) obj.Object {
 	return &obj.String{Value: obj.Types[args[0].Type()]}
 }
 
+// return verdadero if the value is an error, the errors are values when they
+// were caught by excepto or created with the error builtin
+func isError(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("es_error", len(args), 1)
+	}
+
+	if _, isErr := args[0].(*obj.Error); isErr {
+		return obj.SingletonTRUE
+	}
+
+	return obj.SingletonFALSE
+}
+
+// create an error with the given message used as a value, unlike lanzar
+// the error does not stop the evaluation
+func newErrorValue(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("error", len(args), 1)
+	}
+
+	message, isStr := obj.AsText(args[0])
+	if !isStr {
+		return unsoportedArgumentType("error", obj.Types[args[0].Type()])
+	}
+
+	return &obj.Error{Message: message.Value, Handled: true}
+}
+
+// return verdadero if both arguments are the same object, the lists, maps and
+// the rest of mutable objects are compared by reference and the primitives
+// by value, unlike == an integer and a float are never the same
+func same(args ...obj.Object) obj.Object {
+	if len(args) != 2 {
+		return wrongNumberofArgs("mismo", len(args), 2)
+	}
+
+	var isSame bool
+	switch args[0].(type) {
+	case *obj.Number, *obj.Float, *obj.String, *obj.Char, *obj.Bool, *obj.Null, *obj.Time:
+		isSame = reflect.DeepEqual(args[0], args[1])
+
+	default:
+		isSame = args[0] == args[1]
+	}
+
+	if isSame {
+		return obj.SingletonTRUE
+	}
+
+	return obj.SingletonFALSE
+}
+
+// return a frozen copy of the list or map, including the nested ones, so any
+// modification of the copy returns an error. the original is not changed
+func freeze(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("congelar", len(args), 1)
+	}
+
+	switch args[0].(type) {
+	case *obj.List, *obj.Map:
+		return obj.Freeze(args[0])
+
+	default:
+		return unsoportedArgumentType("congelar", obj.Types[args[0].Type()])
+	}
+}
+
+// return verdadero if the list or map was frozen with congelar
+func isFrozen(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("esta_congelado", len(args), 1)
+	}
+
+	if obj.IsFrozen(args[0]) {
+		return obj.SingletonTRUE
+	}
+
+	return obj.SingletonFALSE
+}
+
 func sum(args ...obj.Object) obj.Object {
 	if len(args) > 1 || len(args) < 1 {
 		return wrongNumberofArgs("sum", len(args), 1)
@@ -243,22 +406,172 @@ func sum(args ...obj.Object) obj.Object {
 	return unsoportedArgumentType("suma", obj.Types[args[0].Type()])
 }
 
-// return the absolute value of the given number
-func abs(args ...obj.Object) obj.Object {
-	if len(args) > 1 || len(args) == 0 {
-		return wrongNumberofArgs("abs", len(args), 1)
+// convert an iterable (list, range, set, map keys or string) in a new list
+func toList(args ...obj.Object) obj.Object {
+	if len(args) > 1 {
+		return wrongNumberofArgs("lista", len(args), 1)
 	}
 
-	switch node := args[0].(type) {
+	list := &obj.List{Values: []obj.Object{}}
+	if len(args) == 0 {
+		return list
+	}
+
+	values, err := iterableValues("lista", args[0])
+	if err != nil {
+		return err
+	}
+
+	list.Values = append(list.Values, values...)
+	return list
+}
+
+// return the values of an iterable used by the constructor builtins
+func iterableValues(funcName string, iterable obj.Object) ([]obj.Object, *obj.Error) {
+	switch iterable := iterable.(type) {
+	case *obj.List:
+		return iterable.Values, nil
+
+	case *obj.Map:
+		return iterable.Keys, nil
+
+	case *obj.Set:
+		return iterable.Values, nil
+
+	case *obj.String:
+		values := make([]obj.Object, 0, utf8.RuneCountInString(iterable.Value))
+		for _, char := range iterable.Value {
+			values = append(values, &obj.String{Value: string(char)})
+		}
+		return values, nil
+
+	default:
+		return nil, unsoportedArgumentType(funcName, obj.Types[iterable.Type()])
+	}
+}
+
+// build a set with the values of an iterable (list, set, map keys or string)
+// like conjunto(lista[1, 2, 2])
+func toSet(args ...obj.Object) obj.Object {
+	if len(args) > 1 {
+		return wrongNumberofArgs("conjunto", len(args), 1)
+	}
+
+	if len(args) == 0 {
+		return obj.NewSet()
+	}
+
+	values, err := iterableValues("conjunto", args[0])
+	if err != nil {
+		return err
+	}
+
+	return obj.NewSet(values...)
+}
+
+// build a map from a list of key value pairs like mapa(lista[lista["a", 1]])
+func toMap(args ...obj.Object) obj.Object {
+	if len(args) > 1 {
+		return wrongNumberofArgs("mapa", len(args), 1)
+	}
+
+	mapObj := &obj.Map{Store: map[string]obj.Object{}}
+	if len(args) == 0 {
+		return mapObj
+	}
+
+	pairs, isList := args[0].(*obj.List)
+	if !isList {
+		return unsoportedArgumentType("mapa", obj.Types[args[0].Type()])
+	}
+
+	for _, value := range pairs.Values {
+		pair, isList := value.(*obj.List)
+		if !isList || len(pair.Values) != 2 {
+			return &obj.Error{Message: fmt.Sprintf("mapa requiere pares de llave y valor, se recibio %s", value.Inspect())}
+		}
+
+		if err := mapObj.SetValues(pair.Values[0], pair.Values[1]); err != nil {
+			return &obj.Error{Message: err.Error()}
+		}
+	}
+
+	return mapObj
+}
+
+// return the code point of a single character string, codigo("A") -> 65
+func codePoint(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("codigo", len(args), 1)
+	}
+
+	str, isStr := obj.AsText(args[0])
+	if !isStr {
+		return unsoportedArgumentType("codigo", obj.Types[args[0].Type()])
+	}
+
+	if utf8.RuneCountInString(str.Value) != 1 {
+		return &obj.Error{Message: fmt.Sprintf("codigo requiere un solo caracter, se recibio %q", str.Value)}
+	}
+
+	char, _ := utf8.DecodeRuneInString(str.Value)
+	return &obj.Number{Value: int(char)}
+}
+
+// return the character of a code point, caracter(65) -> "A"
+func character(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("caracter", len(args), 1)
+	}
+
+	num, isNum := args[0].(*obj.Number)
+	if !isNum {
+		return unsoportedArgumentType("caracter", obj.Types[args[0].Type()])
+	}
+
+	if num.Value < 0 || num.Value > utf8.MaxRune || !utf8.ValidRune(rune(num.Value)) {
+		return &obj.Error{Message: fmt.Sprintf("%d no es un codigo de caracter valido", num.Value)}
+	}
+
+	return &obj.String{Value: string(rune(num.Value))}
+}
+
+// format a number with the given decimals and thousands separator like:
+//
+//	formato_numero(1234567.891, 2, ",") -> "1,234,567.89"
+//
+// if the thousands separator is a dot the decimals are separated by a comma
+func formatNumber(args ...obj.Object) obj.Object {
+	if len(args) != 3 {
+		return wrongNumberofArgs("formato_numero", len(args), 3)
+	}
+
+	decimals, isNum := args[1].(*obj.Number)
+	if !isNum || decimals.Value < 0 {
+		return &obj.Error{Message: "los decimales para formato_numero deben ser un entero mayor o igual a 0"}
+	}
+
+	separator, isStr := args[2].(*obj.String)
+	if !isStr {
+		return unsoportedArgumentType("formato_numero", obj.Types[args[2].Type()])
+	}
+
+	var formatted string
+	switch number := args[0].(type) {
 	case *obj.Number:
-		return &obj.Number{Value: int(math.Abs(float64(node.Value)))}
+		formatted = strconv.Itoa(number.Value)
+		if decimals.Value > 0 {
+			formatted += "." + strings.Repeat("0", decimals.Value)
+		}
 
 	case *obj.Float:
-		return &obj.Float{Value: math.Abs(node.Value)}
+		formatted = strconv.FormatFloat(number.Value, 'f', decimals.Value, 64)
 
 	default:
-		return unsoportedArgumentType("abs", obj.Types[args[0].Type()])
+		return unsoportedArgumentType("formato_numero", obj.Types[args[0].Type()])
 	}
+
+	return &obj.String{Value: groupThousands(formatted, separator.Value)}
 }
 
 // input function to recibe input from console
@@ -279,31 +592,184 @@ func toInt(str string) obj.Object {
 }
 
 var BUILTINS = map[string]*obj.Builtin{
-	"largo":        obj.NewBuiltin(Longitud),
-	"escribir":     obj.NewBuiltin(Escribir),
-	"recibir":      obj.NewBuiltin(Recibir),
-	"tipo":         obj.NewBuiltin(Tipo),
-	"entero":       obj.NewBuiltin(castInt),
-	"texto":        obj.NewBuiltin(castString),
-	"rango":        obj.NewBuiltin(rango),
-	"agregar":      obj.NewBuiltin(add),
-	"pop":          obj.NewBuiltin(pop),
-	"popIndice":    obj.NewBuiltin(remove),
-	"contiene":     obj.NewBuiltin(contains),
-	"valores":      obj.NewBuiltin(values),
-	"mayusculas":   obj.NewBuiltin(toUppper),
-	"minusculas":   obj.NewBuiltin(toLower),
-	"dormir":       obj.NewBuiltin(slep),
-	"es_mayuscula": obj.NewBuiltin(isUpper),
-	"es_minuscula": obj.NewBuiltin(isLower),
-	"formatear":    obj.NewBuiltin(formatrArgs),
-	"escribirF":    obj.NewBuiltin(printF),
-	"map":          obj.NewBuiltin(mapList),
-	"porCada":      obj.NewBuiltin(forEach),
-	"filtrar":      obj.NewBuiltin(filter),
-	"contar":       obj.NewBuiltin(count),
-	"separar":      obj.NewBuiltin(split),
-	"abs":          obj.NewBuiltin(abs),
-	"flotante":     obj.NewBuiltin(castFloat),
-	"suma":         obj.NewBuiltin(sum),
+	"largo":               obj.NewBuiltin(Longitud),
+	"recibir":             obj.NewBuiltin(Recibir),
+	"tipo":                obj.NewBuiltin(Tipo),
+	"es_error":            obj.NewBuiltin(isError),
+	"mismo":               obj.NewBuiltin(same),
+	"congelar":            obj.NewBuiltin(freeze),
+	"esta_congelado":      obj.NewBuiltin(isFrozen),
+	"hash":                obj.NewBuiltin(hash),
+	"inspeccionar":        obj.NewBuiltin(inspect),
+	"memoria":             obj.NewBuiltin(memory),
+	"error":               obj.NewBuiltin(newErrorValue),
+	"entero":              obj.NewBuiltin(castInt),
+	"texto":               obj.NewBuiltin(castString),
+	"rango":               obj.NewBuiltin(rango),
+	"agregar":             obj.NewBuiltin(add),
+	"pop":                 obj.NewBuiltin(pop),
+	"popIndice":           obj.NewBuiltin(remove),
+	"contiene":            obj.NewBuiltin(contains),
+	"indice":              obj.NewBuiltin(index),
+	"unico":               obj.NewBuiltin(unique),
+	"aplanar_profundo":    obj.NewBuiltin(deepFlatten),
+	"valores":             obj.NewBuiltin(values),
+	"mayusculas":          obj.NewBuiltin(toUppper),
+	"minusculas":          obj.NewBuiltin(toLower),
+	"dormir":              obj.NewBuiltin(slep),
+	"es_mayuscula":        obj.NewBuiltin(isUpper),
+	"es_minuscula":        obj.NewBuiltin(isLower),
+	"formatear":           obj.NewBuiltin(formatrArgs),
+	"map":                 obj.NewBuiltin(mapList),
+	"porCada":             obj.NewBuiltin(forEach),
+	"mapear_paralelo":     obj.NewBuiltin(parallelMap),
+	"filtrar":             obj.NewBuiltin(filter),
+	"contar":              obj.NewBuiltin(count),
+	"separar":             obj.NewBuiltin(split),
+	"empieza_con":         obj.NewBuiltin(startsWith),
+	"termina_con":         obj.NewBuiltin(endsWith),
+	"rellenar_izquierda":  obj.NewBuiltin(padLeft),
+	"rellenar_derecha":    obj.NewBuiltin(padRight),
+	"subcadena":           obj.NewBuiltin(substring),
+	"codigo":              obj.NewBuiltin(codePoint),
+	"tomar":               obj.NewBuiltin(take),
+	"saltar":              obj.NewBuiltin(skip),
+	"sublista":            obj.NewBuiltin(sublist),
+	"agrupar":             obj.NewBuiltin(chunk),
+	"lista":               obj.NewBuiltin(toList),
+	"mapa":                obj.NewBuiltin(toMap),
+	"conjunto":            obj.NewBuiltin(toSet),
+	"quitar":              obj.NewBuiltin(discard),
+	"union":               obj.NewBuiltin(union),
+	"interseccion":        obj.NewBuiltin(intersection),
+	"diferencia":          obj.NewBuiltin(difference),
+	"enviar":              obj.NewBuiltin(send),
+	"esperar":             obj.NewBuiltin(wait),
+	"caracter":            obj.NewBuiltin(character),
+	"abs":                 obj.NewBuiltin(abs),
+	"signo":               obj.NewBuiltin(sign),
+	"limitar":             obj.NewBuiltin(clamp),
+	"flotante":            obj.NewBuiltin(castFloat),
+	"suma":                obj.NewBuiltin(sum),
+	"formato_numero":      obj.NewBuiltin(formatNumber),
+	"ahora":               obj.NewBuiltin(now),
+	"fecha":               obj.NewBuiltin(date),
+	"formato_fecha":       obj.NewBuiltin(formatDate),
+	"sumar_dias":          obj.NewBuiltin(addDays),
+	"sumar_segundos":      obj.NewBuiltin(addSeconds),
+	"diferencia_segundos": obj.NewBuiltin(secondsBetween),
+	"coincide":            obj.NewBuiltin(match),
+	"extraer":             obj.NewBuiltin(extract),
+	"reemplazar_regex":    obj.NewBuiltin(replaceRegex),
+	"a_json":              obj.NewBuiltin(toJSON),
+	"de_json":             obj.NewBuiltin(fromJSON),
+}
+
+// represents the methods that only exist for one type, like fecha:mes(). they
+// are not global functions, the method calls look them up before the builtins
+var METHODS = map[string]*obj.Builtin{
+	"anio": obj.NewBuiltin(year),
+	"mes":  obj.NewBuiltin(month),
+	"dia":  obj.NewBuiltin(day),
+}
+
+// ayuda is registered after the initialization because it reads the builtins
+func init() {
+	BUILTINS["ayuda"] = obj.NewBuiltin(builtinNames)
+}
+
+// return a sorted list with the names of all the builtins
+func builtinNames(args ...obj.Object) obj.Object {
+	if len(args) != 0 {
+		return wrongNumberofArgs("ayuda", len(args), 0)
+	}
+
+	names := make([]string, 0, len(BUILTINS)+len(ENV_BUILTINS))
+	for name := range BUILTINS {
+		names = append(names, name)
+	}
+	for name := range ENV_BUILTINS {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := &obj.List{Values: make([]obj.Object, 0, len(names))}
+	for _, name := range names {
+		list.Values = append(list.Values, &obj.String{Value: name})
+	}
+
+	return list
+}
+
+// represents the builtins that need access to the enviroment where they are called
+var ENV_BUILTINS = map[string]func(env *obj.Enviroment, args ...obj.Object) obj.Object{
+	"http_obtener":   httpGet,
+	"http_enviar":    httpPost,
+	"argumentos":     arguments,
+	"escribir":       Escribir,
+	"escribirF":      printF,
+	"imprimir_error": printError,
+	"perfil":         profile,
+	"canal":          newChannel,
+}
+
+// return the command line arguments stored in the enviroment as a list of strings
+func arguments(env *obj.Enviroment, args ...obj.Object) obj.Object {
+	if len(args) != 0 {
+		return wrongNumberofArgs("argumentos", len(args), 0)
+	}
+
+	list := &obj.List{Values: []obj.Object{}}
+	for _, arg := range env.Args() {
+		list.Values = append(list.Values, &obj.String{Value: arg})
+	}
+
+	return list
+}
+
+// return a map with the memory stats of the interpreter process, the sizes
+// are in bytes
+func memory(args ...obj.Object) obj.Object {
+	if len(args) != 0 {
+		return wrongNumberofArgs("memoria", len(args), 0)
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	result := &obj.Map{Store: map[string]obj.Object{}}
+	result.SetValues(&obj.String{Value: "asignada"}, &obj.Number{Value: int(stats.HeapAlloc)})
+	result.SetValues(&obj.String{Value: "total_asignada"}, &obj.Number{Value: int(stats.TotalAlloc)})
+	result.SetValues(&obj.String{Value: "sistema"}, &obj.Number{Value: int(stats.Sys)})
+	result.SetValues(&obj.String{Value: "objetos"}, &obj.Number{Value: int(stats.HeapObjects)})
+	result.SetValues(&obj.String{Value: "recolecciones"}, &obj.Number{Value: int(stats.NumGC)})
+	result.SetValues(&obj.String{Value: "gorutinas"}, &obj.Number{Value: runtime.NumGoroutine()})
+	return result
+}
+
+// return a map with how many times each node type was evaluated, the
+// profiling must be enabled by the host
+func profile(env *obj.Enviroment, args ...obj.Object) obj.Object {
+	if len(args) != 0 {
+		return wrongNumberofArgs("perfil", len(args), 0)
+	}
+
+	profile := env.Profile()
+	if profile == nil {
+		return &obj.Error{Message: "perfilado deshabilitado para perfil"}
+	}
+
+	counts := profile.Counts()
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &obj.Map{Store: map[string]obj.Object{}}
+	for _, name := range names {
+		result.SetValues(&obj.String{Value: name}, &obj.Number{Value: counts[name]})
+	}
+
+	return result
 }
@@ -0,0 +1,76 @@
+package builtins
+
+import (
+	obj "aura/src/object"
+	"fmt"
+	"hash/fnv"
+)
+
+// return a stable integer hash of the value, equal values have the same hash.
+// the lists and maps must be frozen to be hashed
+func hash(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("hash", len(args), 1)
+	}
+
+	if err := checkHashable(args[0]); err != nil {
+		return err
+	}
+
+	hasher := fnv.New64a()
+	hasher.Write([]byte(new(obj.Map).Serialize(args[0])))
+	return &obj.Number{Value: int(hasher.Sum64())}
+}
+
+// check that the value and all the values inside it can not change
+func checkHashable(value obj.Object) *obj.Error {
+	switch value := value.(type) {
+	case *obj.Number, *obj.Float, *obj.String, *obj.Char, *obj.Bool, *obj.Null:
+		return nil
+
+	case *obj.List:
+		if !value.Frozen {
+			return unhashable(value)
+		}
+
+		for _, element := range value.Values {
+			if err := checkHashable(element); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case *obj.Map:
+		if !value.Frozen {
+			return unhashable(value)
+		}
+
+		for _, key := range value.Keys {
+			if err := checkHashable(key); err != nil {
+				return err
+			}
+
+			mapValue, _ := value.Get(key)
+			if err := checkHashable(mapValue); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return unhashable(value)
+	}
+}
+
+func unhashable(value obj.Object) *obj.Error {
+	switch value.(type) {
+	case *obj.List:
+		return &obj.Error{Message: "no se puede calcular el hash de una lista sin congelar"}
+
+	case *obj.Map:
+		return &obj.Error{Message: "no se puede calcular el hash de un mapa sin congelar"}
+
+	default:
+		return &obj.Error{Message: fmt.Sprintf("no se puede calcular el hash de un valor de tipo %s", obj.Types[value.Type()])}
+	}
+}
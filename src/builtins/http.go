@@ -0,0 +1,97 @@
+package builtins
+
+import (
+	obj "aura/src/object"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// content type used by http_enviar when is not given
+const defaultContentType = "text/plain; charset=utf-8"
+
+// make a GET request to the url and return a map with the status and the body
+func httpGet(env *obj.Enviroment, args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("http_obtener", len(args), 1)
+	}
+
+	client, err := httpClient("http_obtener", env)
+	if err != nil {
+		return err
+	}
+
+	url, isStr := args[0].(*obj.String)
+	if !isStr {
+		return unsoportedArgumentType("http_obtener", obj.Types[args[0].Type()])
+	}
+
+	return doRequest(client, "http_obtener", url.Value, http.MethodGet, "", nil)
+}
+
+// make a POST request to the url with the given body, optionally the content
+// type can be passed as third argument
+func httpPost(env *obj.Enviroment, args ...obj.Object) obj.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return wrongNumberofArgs("http_enviar", len(args), 2)
+	}
+
+	client, err := httpClient("http_enviar", env)
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		if _, isStr := arg.(*obj.String); !isStr {
+			return unsoportedArgumentType("http_enviar", obj.Types[arg.Type()])
+		}
+	}
+
+	contentType := defaultContentType
+	if len(args) == 3 {
+		contentType = args[2].(*obj.String).Value
+	}
+
+	url, body := args[0].(*obj.String).Value, args[1].(*obj.String).Value
+	return doRequest(client, "http_enviar", url, http.MethodPost, contentType, strings.NewReader(body))
+}
+
+// return a client with the timeout of the enviroment or an error if the
+// network access is disabled
+func httpClient(funcName string, env *obj.Enviroment) (*http.Client, *obj.Error) {
+	network := env.Network()
+	if network == nil {
+		return nil, &obj.Error{Message: fmt.Sprintf("acceso a la red deshabilitado para %s", funcName)}
+	}
+
+	return &http.Client{Timeout: network.Timeout}, nil
+}
+
+// send the request and wrap the response in a map with the keys estado and cuerpo
+func doRequest(client *http.Client, funcName, url, method, contentType string, body io.Reader) obj.Object {
+	request, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return &obj.Error{Message: fmt.Sprintf("error en %s: %s", funcName, err)}
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return &obj.Error{Message: fmt.Sprintf("error en %s: %s", funcName, err)}
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return &obj.Error{Message: fmt.Sprintf("error en %s: %s", funcName, err)}
+	}
+
+	result := &obj.Map{Store: map[string]obj.Object{}}
+	result.SetValues(&obj.String{Value: "estado"}, &obj.Number{Value: response.StatusCode})
+	result.SetValues(&obj.String{Value: "cuerpo"}, &obj.String{Value: string(content)})
+	return result
+}
@@ -0,0 +1,103 @@
+package builtins
+
+import (
+	obj "aura/src/object"
+	"fmt"
+	"strings"
+)
+
+// indentation used by each nesting level of inspeccionar
+const inspectIndent = "  "
+
+// return a detailed representation of the value with the type of each
+// value and the size of the collections like:
+//
+//	mapa(1){
+//	  "a"(texto) => 1(entero)
+//	}
+func inspect(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("inspeccionar", len(args), 1)
+	}
+
+	var out strings.Builder
+	writeInspection(&out, args[0], 0, make(map[obj.Object]bool))
+	return &obj.String{Value: out.String()}
+}
+
+// write the representation of the value in the given nesting level. visiting
+// has the collections that are being written, so a collection that contains
+// itself is written like lista(1)[...]
+func writeInspection(out *strings.Builder, value obj.Object, level int, visiting map[obj.Object]bool) {
+	switch value.(type) {
+	case *obj.List, *obj.Set, *obj.Map:
+		if visiting[value] {
+			writeCycle(out, value)
+			return
+		}
+
+		visiting[value] = true
+		defer delete(visiting, value)
+	}
+
+	switch value := value.(type) {
+	case *obj.List:
+		writeCollection(out, fmt.Sprintf("lista(%d)[", len(value.Values)), "]", len(value.Values), level, func(idx int) {
+			writeInspection(out, value.Values[idx], level+1, visiting)
+		})
+
+	case *obj.Set:
+		writeCollection(out, fmt.Sprintf("conjunto(%d){", len(value.Values)), "}", len(value.Values), level, func(idx int) {
+			writeInspection(out, value.Values[idx], level+1, visiting)
+		})
+
+	case *obj.Map:
+		writeCollection(out, fmt.Sprintf("mapa(%d){", len(value.Keys)), "}", len(value.Keys), level, func(idx int) {
+			key := value.Keys[idx]
+			writeInspection(out, key, level+1, visiting)
+			out.WriteString(" => ")
+			mapValue, _ := value.Get(key)
+			writeInspection(out, mapValue, level+1, visiting)
+		})
+
+	case *obj.String:
+		fmt.Fprintf(out, "%q(%s)", value.Value, obj.Types[value.Type()])
+
+	default:
+		fmt.Fprintf(out, "%s(%s)", value.Inspect(), obj.Types[value.Type()])
+	}
+}
+
+// write a collection that is already being written without its elements
+func writeCycle(out *strings.Builder, value obj.Object) {
+	switch value := value.(type) {
+	case *obj.List:
+		fmt.Fprintf(out, "lista(%d)[...]", len(value.Values))
+
+	case *obj.Set:
+		fmt.Fprintf(out, "conjunto(%d){...}", len(value.Values))
+
+	case *obj.Map:
+		fmt.Fprintf(out, "mapa(%d){...}", len(value.Keys))
+	}
+}
+
+// write a collection with one element per line indented one level more
+// than the collection
+func writeCollection(out *strings.Builder, open, close string, size, level int, writeElement func(idx int)) {
+	out.WriteString(open)
+	if size == 0 {
+		out.WriteString(close)
+		return
+	}
+
+	for idx := 0; idx < size; idx++ {
+		out.WriteString("\n" + strings.Repeat(inspectIndent, level+1))
+		writeElement(idx)
+		if idx < size-1 {
+			out.WriteString(",")
+		}
+	}
+
+	out.WriteString("\n" + strings.Repeat(inspectIndent, level) + close)
+}
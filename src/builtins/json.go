@@ -0,0 +1,285 @@
+package builtins
+
+import (
+	obj "aura/src/object"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// indentation used by each nesting level of a_json when is pretty printed
+const jsonIndent = "  "
+
+// convert the value to a json text, the maps must have text keys and they are
+// written sorted so equal maps always give the same json. if the second
+// argument is true the json is indented to be read by humans
+func toJSON(args ...obj.Object) obj.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return wrongNumberofArgs("a_json", len(args), 1)
+	}
+
+	pretty := false
+	if len(args) == 2 {
+		flag, isBool := args[1].(*obj.Bool)
+		if !isBool {
+			return unsoportedArgumentType("a_json", obj.Types[args[1].Type()])
+		}
+		pretty = flag.Value
+	}
+
+	var out strings.Builder
+	if err := writeJSON(&out, args[0], make(map[obj.Object]bool)); err != nil {
+		return err
+	}
+
+	if !pretty {
+		return &obj.String{Value: out.String()}
+	}
+
+	var indented bytes.Buffer
+	json.Indent(&indented, []byte(out.String()), "", jsonIndent)
+	return &obj.String{Value: indented.String()}
+}
+
+// return the text of a map key, the characters are the same key of the text
+// with the character
+func jsonKey(key obj.Object) (string, bool) {
+	switch key := key.(type) {
+	case *obj.String:
+		return key.Value, true
+
+	case *obj.Char:
+		return string(key.Value), true
+
+	default:
+		return "", false
+	}
+}
+
+// write the json of the value. visiting has the collections that are being
+// written because a collection that contains itself can not be converted
+func writeJSON(out *strings.Builder, value obj.Object, visiting map[obj.Object]bool) *obj.Error {
+	switch value.(type) {
+	case *obj.List, *obj.Set, *obj.Map:
+		if visiting[value] {
+			return &obj.Error{Message: "no se puede convertir a json una referencia circular"}
+		}
+
+		visiting[value] = true
+		defer delete(visiting, value)
+	}
+
+	switch value := value.(type) {
+	case *obj.Number:
+		out.WriteString(strconv.Itoa(value.Value))
+
+	case *obj.Float:
+		if math.IsInf(value.Value, 0) || math.IsNaN(value.Value) {
+			return &obj.Error{Message: fmt.Sprintf("no se puede convertir a json el numero %s", value.Inspect())}
+		}
+		out.WriteString(strconv.FormatFloat(value.Value, 'g', -1, 64))
+
+	case *obj.String:
+		encoded, _ := json.Marshal(value.Value)
+		out.Write(encoded)
+
+	case *obj.Char:
+		// a character is written like the text with the character
+		encoded, _ := json.Marshal(string(value.Value))
+		out.Write(encoded)
+
+	case *obj.Bool:
+		out.WriteString(strconv.FormatBool(value.Value))
+
+	case *obj.Null:
+		out.WriteString("null")
+
+	case *obj.List:
+		return writeJSONArray(out, value.Values, visiting)
+
+	case *obj.Set:
+		return writeJSONArray(out, value.Values, visiting)
+
+	case *obj.Map:
+		return writeJSONObject(out, value, visiting)
+
+	default:
+		return unsoportedArgumentType("a_json", obj.Types[value.Type()])
+	}
+
+	return nil
+}
+
+// write the map as a json object with the keys sorted
+func writeJSONObject(out *strings.Builder, value *obj.Map, visiting map[obj.Object]bool) *obj.Error {
+	keys := make([]string, len(value.Keys))
+	for idx, key := range value.Keys {
+		text, isText := jsonKey(key)
+		if !isText {
+			return &obj.Error{Message: fmt.Sprintf("las llaves deben ser texto para a_json, se obtuvo %s", obj.Types[key.Type()])}
+		}
+		keys[idx] = text
+	}
+
+	order := make([]int, len(value.Keys))
+	for idx := range order {
+		order[idx] = idx
+	}
+	sort.SliceStable(order, func(i, j int) bool { return keys[order[i]] < keys[order[j]] })
+
+	out.WriteString("{")
+	for idx, position := range order {
+		if idx > 0 {
+			out.WriteString(",")
+		}
+
+		encoded, _ := json.Marshal(keys[position])
+		out.Write(encoded)
+		out.WriteString(":")
+
+		mapValue, _ := value.Get(value.Keys[position])
+		if err := writeJSON(out, mapValue, visiting); err != nil {
+			return err
+		}
+	}
+	out.WriteString("}")
+
+	return nil
+}
+
+// write the values as a json array
+func writeJSONArray(out *strings.Builder, values []obj.Object, visiting map[obj.Object]bool) *obj.Error {
+	out.WriteString("[")
+	for idx, value := range values {
+		if idx > 0 {
+			out.WriteString(",")
+		}
+
+		if err := writeJSON(out, value, visiting); err != nil {
+			return err
+		}
+	}
+	out.WriteString("]")
+	return nil
+}
+
+// convert a json text to the values of the lenguage. the objects are converted
+// to maps in the same order of the text, and the numbers without decimals
+// are converted to integers
+func fromJSON(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("de_json", len(args), 1)
+	}
+
+	str, isStr := args[0].(*obj.String)
+	if !isStr {
+		return unsoportedArgumentType("de_json", obj.Types[args[0].Type()])
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(str.Value))
+	decoder.UseNumber()
+
+	value, err := decodeJSON(decoder)
+	if err != nil {
+		return invalidJSON(err)
+	}
+
+	// only one value is allowed in the text
+	if _, err := decoder.Token(); err != io.EOF {
+		return &obj.Error{Message: "json no valido para de_json: contenido despues del valor"}
+	}
+
+	return value
+}
+
+// return the error for a text that is not a valid json
+func invalidJSON(err error) *obj.Error {
+	if syntaxErr, isSyntax := err.(*json.SyntaxError); isSyntax {
+		return &obj.Error{Message: fmt.Sprintf("json no valido para de_json en la posicion %d", syntaxErr.Offset)}
+	}
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return &obj.Error{Message: "json no valido para de_json: fin inesperado"}
+	}
+
+	return &obj.Error{Message: "json no valido para de_json"}
+}
+
+// decode the next value of the decoder
+func decodeJSON(decoder *json.Decoder) (obj.Object, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			list := &obj.List{Values: []obj.Object{}}
+			for decoder.More() {
+				value, err := decodeJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				list.Values = append(list.Values, value)
+			}
+
+			// the closing bracket
+			_, err := decoder.Token()
+			return list, err
+		}
+
+		result := &obj.Map{Store: map[string]obj.Object{}}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result.UpdateKey(&obj.String{Value: key.(string)}, value)
+		}
+
+		// the closing brace
+		_, err := decoder.Token()
+		return result, err
+
+	case json.Number:
+		return jsonNumber(token), nil
+
+	case string:
+		return &obj.String{Value: token}, nil
+
+	case bool:
+		if token {
+			return obj.SingletonTRUE, nil
+		}
+		return obj.SingletonFALSE, nil
+
+	default:
+		return obj.NullVAlue, nil
+	}
+}
+
+// convert the json number to an integer if it does not have decimals and
+// fits in an integer, otherwise to a float
+func jsonNumber(number json.Number) obj.Object {
+	if integer, err := strconv.Atoi(number.String()); err == nil {
+		return &obj.Number{Value: integer}
+	}
+
+	float, _ := strconv.ParseFloat(number.String(), 64)
+	if float == math.Trunc(float) && float >= math.MinInt64 && float < math.MaxInt64 {
+		return &obj.Number{Value: int(float)}
+	}
+
+	return &obj.Float{Value: float}
+}
@@ -2,6 +2,8 @@ package builtins
 
 import (
 	obj "aura/src/object"
+	"fmt"
+	"runtime"
 )
 
 func add(args ...obj.Object) obj.Object {
@@ -9,11 +11,7 @@ func add(args ...obj.Object) obj.Object {
 		return wrongNumberofArgs("agregar", len(args), 1)
 	}
 
-	if num, isNumber := args[0].(*obj.Number); isNumber {
-		return obj.NewMethod(num, obj.APPEND)
-	}
-
-	return unsoportedArgumentType("add", obj.Types[args[0].Type()])
+	return obj.NewMethod(args[0], obj.APPEND)
 }
 
 func remove(args ...obj.Object) obj.Object {
@@ -44,6 +42,30 @@ func contains(args ...obj.Object) obj.Object {
 	return obj.NewMethod(args[0], obj.CONTAIS)
 }
 
+func index(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("indice", len(args), 1)
+	}
+
+	return obj.NewMethod(args[0], obj.INDEX)
+}
+
+func unique(args ...obj.Object) obj.Object {
+	if len(args) > 0 {
+		return wrongNumberofArgs("unico", len(args), 0)
+	}
+
+	return obj.NewMethod(obj.SingletonNUll, obj.UNIQUE)
+}
+
+func deepFlatten(args ...obj.Object) obj.Object {
+	if len(args) > 0 {
+		return wrongNumberofArgs("aplanar_profundo", len(args), 0)
+	}
+
+	return obj.NewMethod(obj.SingletonNUll, obj.DEEPFLATTEN)
+}
+
 func values(args ...obj.Object) obj.Object {
 	if len(args) > 0 {
 		return wrongNumberofArgs("valores", len(args), 0)
@@ -100,6 +122,37 @@ func mapList(args ...obj.Object) obj.Object {
 	return &obj.Error{Message: "se requiere una funcion para map"}
 }
 
+// the number of workers is optional, by default is the number of cpus
+func parallelMap(args ...obj.Object) obj.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return wrongNumberofArgs("mapear_paralelo", len(args), 1)
+	}
+
+	fn, isFn := args[0].(*obj.Def)
+	if !isFn {
+		return &obj.Error{Message: "se requiere una funcion para mapear_paralelo"}
+	}
+
+	if len(fn.Parameters) != 1 {
+		return &obj.Error{Message: "La funcion para mapear_paralelo solo puede recibir un argumento"}
+	}
+
+	workers := &obj.Number{Value: runtime.NumCPU()}
+	if len(args) == 2 {
+		num, isNum := args[1].(*obj.Number)
+		if !isNum {
+			return unsoportedArgumentType("mapear_paralelo", obj.Types[args[1].Type()])
+		}
+
+		if num.Value <= 0 {
+			return &obj.Error{Message: fmt.Sprintf("mapear_paralelo requiere al menos un trabajador, se recibio %d", num.Value)}
+		}
+		workers = num
+	}
+
+	return obj.NewMethod(&obj.List{Values: []obj.Object{fn, workers}}, obj.PARALLELMAP)
+}
+
 func forEach(args ...obj.Object) obj.Object {
 	if len(args) > 1 || len(args) == 0 {
 		return wrongNumberofArgs("porCada", len(args), 1)
@@ -153,9 +206,197 @@ func split(args ...obj.Object) obj.Object {
 		return wrongNumberofArgs("separar", len(args), 1)
 	}
 
-	if str, isStr := args[0].(*obj.String); isStr {
+	if str, isStr := obj.AsText(args[0]); isStr {
 		return obj.NewMethod(str, obj.SPLIT)
 	}
 
 	return unsoportedArgumentType("separar", obj.Types[args[0].Type()])
 }
+
+func startsWith(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("empieza_con", len(args), 1)
+	}
+
+	if str, isStr := obj.AsText(args[0]); isStr {
+		return obj.NewMethod(str, obj.STARTSWITH)
+	}
+
+	return unsoportedArgumentType("empieza_con", obj.Types[args[0].Type()])
+}
+
+func endsWith(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("termina_con", len(args), 1)
+	}
+
+	if str, isStr := obj.AsText(args[0]); isStr {
+		return obj.NewMethod(str, obj.ENDSWITH)
+	}
+
+	return unsoportedArgumentType("termina_con", obj.Types[args[0].Type()])
+}
+
+func padLeft(args ...obj.Object) obj.Object {
+	return padArgs("rellenar_izquierda", obj.PADLEFT, args)
+}
+
+func padRight(args ...obj.Object) obj.Object {
+	return padArgs("rellenar_derecha", obj.PADRIGHT, args)
+}
+
+// validate the width and the optional pad string of the pad methods,
+// the pad string is a space by default
+func padArgs(funcName string, methodType obj.MethodsTypes, args []obj.Object) obj.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return wrongNumberofArgs(funcName, len(args), 2)
+	}
+
+	width, isNum := args[0].(*obj.Number)
+	if !isNum {
+		return unsoportedArgumentType(funcName, obj.Types[args[0].Type()])
+	}
+
+	pad := &obj.String{Value: " "}
+	if len(args) == 2 {
+		str, isStr := obj.AsText(args[1])
+		if !isStr {
+			return unsoportedArgumentType(funcName, obj.Types[args[1].Type()])
+		}
+
+		if str.Value == "" {
+			return &obj.Error{Message: fmt.Sprintf("el relleno de %s no puede estar vacio", funcName)}
+		}
+		pad = str
+	}
+
+	return obj.NewMethod(&obj.List{Values: []obj.Object{width, pad}}, methodType)
+}
+
+// the end of the substring is optional, without it the substring goes
+// until the end of the string
+func substring(args ...obj.Object) obj.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return wrongNumberofArgs("subcadena", len(args), 2)
+	}
+
+	for _, arg := range args {
+		if _, isNum := arg.(*obj.Number); !isNum {
+			return unsoportedArgumentType("subcadena", obj.Types[arg.Type()])
+		}
+	}
+
+	return obj.NewMethod(&obj.List{Values: args}, obj.SUBSTRING)
+}
+
+func take(args ...obj.Object) obj.Object {
+	return countArg("tomar", obj.TAKE, args)
+}
+
+func skip(args ...obj.Object) obj.Object {
+	return countArg("saltar", obj.SKIP, args)
+}
+
+func countArg(funcName string, methodType obj.MethodsTypes, args []obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs(funcName, len(args), 1)
+	}
+
+	if num, isNum := args[0].(*obj.Number); isNum {
+		return obj.NewMethod(num, methodType)
+	}
+
+	return unsoportedArgumentType(funcName, obj.Types[args[0].Type()])
+}
+
+func sublist(args ...obj.Object) obj.Object {
+	if len(args) != 2 {
+		return wrongNumberofArgs("sublista", len(args), 2)
+	}
+
+	for _, arg := range args {
+		if _, isNum := arg.(*obj.Number); !isNum {
+			return unsoportedArgumentType("sublista", obj.Types[arg.Type()])
+		}
+	}
+
+	return obj.NewMethod(&obj.List{Values: args}, obj.SUBLIST)
+}
+
+func chunk(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("agrupar", len(args), 1)
+	}
+
+	size, isNum := args[0].(*obj.Number)
+	if !isNum {
+		return unsoportedArgumentType("agrupar", obj.Types[args[0].Type()])
+	}
+
+	if size.Value <= 0 {
+		return &obj.Error{Message: fmt.Sprintf("el tamaño de agrupar debe ser positivo, se recibio %d", size.Value)}
+	}
+
+	return obj.NewMethod(size, obj.CHUNK)
+}
+
+func year(args ...obj.Object) obj.Object {
+	if len(args) != 0 {
+		return wrongNumberofArgs("anio", len(args), 0)
+	}
+
+	return obj.NewMethod(obj.SingletonNUll, obj.YEAR)
+}
+
+func month(args ...obj.Object) obj.Object {
+	if len(args) != 0 {
+		return wrongNumberofArgs("mes", len(args), 0)
+	}
+
+	return obj.NewMethod(obj.SingletonNUll, obj.MONTH)
+}
+
+func day(args ...obj.Object) obj.Object {
+	if len(args) != 0 {
+		return wrongNumberofArgs("dia", len(args), 0)
+	}
+
+	return obj.NewMethod(obj.SingletonNUll, obj.DAY)
+}
+
+// return the quitar method to remove a value from a set
+func discard(args ...obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs("quitar", len(args), 1)
+	}
+
+	return obj.NewMethod(args[0], obj.DISCARD)
+}
+
+// return the union method for sets like a:union(b)
+func union(args ...obj.Object) obj.Object {
+	return setOperation("union", obj.UNION, args)
+}
+
+// return the interseccion method for sets like a:interseccion(b)
+func intersection(args ...obj.Object) obj.Object {
+	return setOperation("interseccion", obj.INTERSECTION, args)
+}
+
+// return the diferencia method for sets like a:diferencia(b)
+func difference(args ...obj.Object) obj.Object {
+	return setOperation("diferencia", obj.DIFFERENCE, args)
+}
+
+// validate that the argument of a set operation is other set
+func setOperation(funcName string, methodType obj.MethodsTypes, args []obj.Object) obj.Object {
+	if len(args) != 1 {
+		return wrongNumberofArgs(funcName, len(args), 1)
+	}
+
+	if _, isSet := args[0].(*obj.Set); !isSet {
+		return unsoportedArgumentType(funcName, obj.Types[args[0].Type()])
+	}
+
+	return obj.NewMethod(args[0], methodType)
+}
@@ -0,0 +1,150 @@
+package builtins
+
+import (
+	obj "aura/src/object"
+	"fmt"
+	"math"
+)
+
+// return the absolute value of a number, used by the abs builtin and method
+func Abs(number obj.Object) obj.Object {
+	switch num := number.(type) {
+	case *obj.Number:
+		return &obj.Number{Value: int(math.Abs(float64(num.Value)))}
+
+	case *obj.Float:
+		return &obj.Float{Value: math.Abs(num.Value)}
+
+	default:
+		return unsoportedArgumentType("abs", obj.Types[number.Type()])
+	}
+}
+
+// return -1, 0 or 1 depending on the sign of the number, used by the
+// signo builtin and method
+func Sign(number obj.Object) obj.Object {
+	var value float64
+	switch num := number.(type) {
+	case *obj.Number:
+		value = float64(num.Value)
+
+	case *obj.Float:
+		value = num.Value
+
+	default:
+		return unsoportedArgumentType("signo", obj.Types[number.Type()])
+	}
+
+	switch {
+	case value > 0:
+		return &obj.Number{Value: 1}
+	case value < 0:
+		return &obj.Number{Value: -1}
+	default:
+		return &obj.Number{Value: 0}
+	}
+}
+
+// clamp the number into the range [min, max], the result is an integer only
+// if all the arguments are integers, used by the limitar builtin and method
+func Clamp(number, min, max obj.Object) obj.Object {
+	for _, arg := range []obj.Object{number, min, max} {
+		if !isNumber(arg) {
+			return unsoportedArgumentType("limitar", obj.Types[arg.Type()])
+		}
+	}
+
+	num, isInt := number.(*obj.Number)
+	minInt, isMinInt := min.(*obj.Number)
+	maxInt, isMaxInt := max.(*obj.Number)
+	if isInt && isMinInt && isMaxInt {
+		if minInt.Value > maxInt.Value {
+			return invalidRange(minInt.Inspect(), maxInt.Inspect())
+		}
+
+		switch {
+		case num.Value < minInt.Value:
+			return &obj.Number{Value: minInt.Value}
+		case num.Value > maxInt.Value:
+			return &obj.Number{Value: maxInt.Value}
+		default:
+			return &obj.Number{Value: num.Value}
+		}
+	}
+
+	value, minVal, maxVal := toFloat(number), toFloat(min), toFloat(max)
+	if minVal > maxVal {
+		return invalidRange(min.Inspect(), max.Inspect())
+	}
+
+	return &obj.Float{Value: math.Min(math.Max(value, minVal), maxVal)}
+}
+
+func invalidRange(min, max string) *obj.Error {
+	return &obj.Error{
+		Message: fmt.Sprintf("el minimo no puede ser mayor al maximo en limitar: %s > %s", min, max),
+	}
+}
+
+func isNumber(object obj.Object) bool {
+	switch object.(type) {
+	case *obj.Number, *obj.Float:
+		return true
+	default:
+		return false
+	}
+}
+
+func toFloat(number obj.Object) float64 {
+	if num, isInt := number.(*obj.Number); isInt {
+		return float64(num.Value)
+	}
+
+	return number.(*obj.Float).Value
+}
+
+// without arguments return the abs method for numbers like (-5):abs()
+func abs(args ...obj.Object) obj.Object {
+	if len(args) > 1 {
+		return wrongNumberofArgs("abs", len(args), 1)
+	}
+
+	if len(args) == 0 {
+		return obj.NewMethod(obj.SingletonNUll, obj.ABS)
+	}
+
+	return Abs(args[0])
+}
+
+// without arguments return the signo method for numbers like n:signo()
+func sign(args ...obj.Object) obj.Object {
+	if len(args) > 1 {
+		return wrongNumberofArgs("signo", len(args), 1)
+	}
+
+	if len(args) == 0 {
+		return obj.NewMethod(obj.SingletonNUll, obj.SIGN)
+	}
+
+	return Sign(args[0])
+}
+
+// with two arguments return the limitar method for numbers like n:limitar(0, 10)
+func clamp(args ...obj.Object) obj.Object {
+	switch len(args) {
+	case 2:
+		for _, arg := range args {
+			if !isNumber(arg) {
+				return unsoportedArgumentType("limitar", obj.Types[arg.Type()])
+			}
+		}
+
+		return obj.NewMethod(&obj.List{Values: args}, obj.CLAMP)
+
+	case 3:
+		return Clamp(args[0], args[1], args[2])
+
+	default:
+		return wrongNumberofArgs("limitar", len(args), 3)
+	}
+}
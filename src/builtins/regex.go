@@ -0,0 +1,133 @@
+package builtins
+
+import (
+	obj "aura/src/object"
+	"container/list"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// represents the max number of compiled patterns kept in the cache
+const maxPatterns = 128
+
+// compiled patterns by pattern string, so patterns used in loops are compiled
+// once. the least recently used pattern is removed when the cache is full
+var (
+	patterns      = map[string]*list.Element{}
+	patternsOrder = list.New()
+	patternsMutex sync.Mutex
+)
+
+// represents a compiled pattern in the cache
+type cachedPattern struct {
+	source   string         // represents the pattern as written
+	compiled *regexp.Regexp // represents the compiled pattern
+}
+
+// return the compiled pattern from the cache or compile it
+func compilePattern(funcName string, pattern obj.Object) (*regexp.Regexp, *obj.Error) {
+	str, isStr := pattern.(*obj.String)
+	if !isStr {
+		return nil, unsoportedArgumentType(funcName, obj.Types[pattern.Type()])
+	}
+
+	patternsMutex.Lock()
+	defer patternsMutex.Unlock()
+	if element, exists := patterns[str.Value]; exists {
+		patternsOrder.MoveToFront(element)
+		return element.Value.(*cachedPattern).compiled, nil
+	}
+
+	compiled, err := regexp.Compile(str.Value)
+	if err != nil {
+		return nil, &obj.Error{Message: fmt.Sprintf("patron no valido para %s: %s", funcName, str.Value)}
+	}
+
+	if patternsOrder.Len() >= maxPatterns {
+		oldest := patternsOrder.Back()
+		patternsOrder.Remove(oldest)
+		delete(patterns, oldest.Value.(*cachedPattern).source)
+	}
+
+	patterns[str.Value] = patternsOrder.PushFront(&cachedPattern{source: str.Value, compiled: compiled})
+	return compiled, nil
+}
+
+// return the pattern and the text in the args of a regex builtin
+func patternAndText(funcName string, args []obj.Object) (*regexp.Regexp, *obj.String, *obj.Error) {
+	pattern, err := compilePattern(funcName, args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text, isStr := args[1].(*obj.String)
+	if !isStr {
+		return nil, nil, unsoportedArgumentType(funcName, obj.Types[args[1].Type()])
+	}
+
+	return pattern, text, nil
+}
+
+// check if the text matches the pattern
+func match(args ...obj.Object) obj.Object {
+	if len(args) != 2 {
+		return wrongNumberofArgs("coincide", len(args), 2)
+	}
+
+	pattern, text, err := patternAndText("coincide", args)
+	if err != nil {
+		return err
+	}
+
+	if pattern.MatchString(text.Value) {
+		return obj.SingletonTRUE
+	}
+
+	return obj.SingletonFALSE
+}
+
+// return the capture groups of the first match in the text, if the pattern
+// has no groups the list contains the whole match
+func extract(args ...obj.Object) obj.Object {
+	if len(args) != 2 {
+		return wrongNumberofArgs("extraer", len(args), 2)
+	}
+
+	pattern, text, err := patternAndText("extraer", args)
+	if err != nil {
+		return err
+	}
+
+	list := &obj.List{Values: []obj.Object{}}
+	groups := pattern.FindStringSubmatch(text.Value)
+	if len(groups) > 1 {
+		groups = groups[1:]
+	}
+
+	for _, group := range groups {
+		list.Values = append(list.Values, &obj.String{Value: group})
+	}
+
+	return list
+}
+
+// replace all the matches of the pattern in the text, the replacement
+// can use the groups like $1
+func replaceRegex(args ...obj.Object) obj.Object {
+	if len(args) != 3 {
+		return wrongNumberofArgs("reemplazar_regex", len(args), 3)
+	}
+
+	pattern, text, err := patternAndText("reemplazar_regex", args)
+	if err != nil {
+		return err
+	}
+
+	replacement, isStr := args[2].(*obj.String)
+	if !isStr {
+		return unsoportedArgumentType("reemplazar_regex", obj.Types[args[2].Type()])
+	}
+
+	return &obj.String{Value: pattern.ReplaceAllString(text.Value, replacement.Value)}
+}
@@ -0,0 +1,153 @@
+package builtins
+
+import (
+	obj "aura/src/object"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// return the current date and time in UTC
+func now(args ...obj.Object) obj.Object {
+	if len(args) != 0 {
+		return wrongNumberofArgs("ahora", len(args), 0)
+	}
+
+	return obj.NewTime(time.Now())
+}
+
+// return a new date with the given year, month, day and optionally hour,
+// minute and second like:
+//
+//	fecha(2023, 12, 31, 23, 59, 59)
+func date(args ...obj.Object) obj.Object {
+	if len(args) != 3 && len(args) != 6 {
+		return wrongNumberofArgs("fecha", len(args), 3)
+	}
+
+	values := make([]int, 6)
+	for idx, arg := range args {
+		num, isNum := arg.(*obj.Number)
+		if !isNum {
+			return unsoportedArgumentType("fecha", obj.Types[arg.Type()])
+		}
+
+		values[idx] = num.Value
+	}
+
+	if values[1] < 1 || values[1] > 12 {
+		return &obj.Error{Message: fmt.Sprintf("mes no valido para fecha: %d", values[1])}
+	}
+
+	return obj.NewTime(time.Date(
+		values[0], time.Month(values[1]), values[2], values[3], values[4], values[5], 0, time.UTC,
+	))
+}
+
+// format a date with a pattern like:
+//
+//	formato_fecha(fecha(2023, 1, 2), "%d/%m/%Y") -> "02/01/2023"
+//
+// the pattern accepts %Y, %m, %d, %H, %M, %S and %% for a literal %
+func formatDate(args ...obj.Object) obj.Object {
+	if len(args) != 2 {
+		return wrongNumberofArgs("formato_fecha", len(args), 2)
+	}
+
+	date, isTime := args[0].(*obj.Time)
+	if !isTime {
+		return unsoportedArgumentType("formato_fecha", obj.Types[args[0].Type()])
+	}
+
+	pattern, isStr := args[1].(*obj.String)
+	if !isStr {
+		return unsoportedArgumentType("formato_fecha", obj.Types[args[1].Type()])
+	}
+
+	var buf strings.Builder
+	verb := false
+	for _, char := range pattern.Value {
+		if !verb {
+			if char == '%' {
+				verb = true
+			} else {
+				buf.WriteRune(char)
+			}
+			continue
+		}
+
+		verb = false
+		switch char {
+		case 'Y':
+			buf.WriteString(fmt.Sprintf("%04d", date.Value.Year()))
+		case 'm':
+			buf.WriteString(fmt.Sprintf("%02d", int(date.Value.Month())))
+		case 'd':
+			buf.WriteString(fmt.Sprintf("%02d", date.Value.Day()))
+		case 'H':
+			buf.WriteString(fmt.Sprintf("%02d", date.Value.Hour()))
+		case 'M':
+			buf.WriteString(fmt.Sprintf("%02d", date.Value.Minute()))
+		case 'S':
+			buf.WriteString(fmt.Sprintf("%02d", date.Value.Second()))
+		case '%':
+			buf.WriteRune('%')
+		default:
+			return &obj.Error{Message: fmt.Sprintf("patron no valido para formato_fecha: %%%c", char)}
+		}
+	}
+
+	if verb {
+		return &obj.Error{Message: "patron no valido para formato_fecha: %"}
+	}
+
+	return &obj.String{Value: buf.String()}
+}
+
+// return a new date with the given days added
+func addDays(args ...obj.Object) obj.Object {
+	return addToDate("sumar_dias", 24*time.Hour, args)
+}
+
+// return a new date with the given seconds added
+func addSeconds(args ...obj.Object) obj.Object {
+	return addToDate("sumar_segundos", time.Second, args)
+}
+
+// add the amount of units in the args to the date in the args
+func addToDate(funcName string, unit time.Duration, args []obj.Object) obj.Object {
+	if len(args) != 2 {
+		return wrongNumberofArgs(funcName, len(args), 2)
+	}
+
+	date, isTime := args[0].(*obj.Time)
+	if !isTime {
+		return unsoportedArgumentType(funcName, obj.Types[args[0].Type()])
+	}
+
+	amount, isNum := args[1].(*obj.Number)
+	if !isNum {
+		return unsoportedArgumentType(funcName, obj.Types[args[1].Type()])
+	}
+
+	return obj.NewTime(date.Value.Add(time.Duration(amount.Value) * unit))
+}
+
+// return the seconds between two dates
+func secondsBetween(args ...obj.Object) obj.Object {
+	if len(args) != 2 {
+		return wrongNumberofArgs("diferencia_segundos", len(args), 2)
+	}
+
+	start, isTime := args[0].(*obj.Time)
+	if !isTime {
+		return unsoportedArgumentType("diferencia_segundos", obj.Types[args[0].Type()])
+	}
+
+	end, isTime := args[1].(*obj.Time)
+	if !isTime {
+		return unsoportedArgumentType("diferencia_segundos", obj.Types[args[1].Type()])
+	}
+
+	return &obj.Number{Value: int(end.Value.Sub(start.Value) / time.Second)}
+}
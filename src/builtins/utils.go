@@ -88,3 +88,37 @@ func formatString(str string, args []obj.Object) string {
 
 	return str
 }
+
+// add the separator between each group of three digits in the integer part of
+// the given formatted number
+func groupThousands(number, separator string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+
+	integer, fraction, hasFraction := strings.Cut(number, ".")
+	if strings.Trim(integer+fraction, "0") == "" {
+		// we dont want -0.00 when a small negative number is rounded
+		sign = ""
+	}
+
+	var buf strings.Builder
+	buf.WriteString(sign)
+	for idx, digit := range integer {
+		if idx > 0 && (len(integer)-idx)%3 == 0 {
+			buf.WriteString(separator)
+		}
+		buf.WriteRune(digit)
+	}
+
+	if hasFraction {
+		decimalSeparator := "."
+		if separator == "." {
+			decimalSeparator = ","
+		}
+		buf.WriteString(decimalSeparator + fraction)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,140 @@
+package evaluator
+
+import (
+	obj "aura/src/object"
+)
+
+// return the value of a numeric object promoted to float
+func toFloat(object obj.Object) (float64, bool) {
+	switch num := object.(type) {
+	case *obj.Number:
+		return float64(num.Value), true
+
+	case *obj.Float:
+		return num.Value, true
+
+	default:
+		return 0, false
+	}
+}
+
+// check if the object is an integer or a float
+func isNumeric(object obj.Object) bool {
+	_, isNum := toFloat(object)
+	return isNum
+}
+
+// return the simple operator of a compound assignment like += or the
+// same operator if is not a compound assignment
+func baseOperator(operator string) string {
+	switch operator {
+	case "+=", "-=", "*=", "/=":
+		return operator[:1]
+
+	default:
+		return operator
+	}
+}
+
+// apply an arithmetic operator between two numbers, the integers are
+// promoted to float when the other operand is a float
+func arithmeticObjects(operator string, left, rigth obj.Object) obj.Object {
+	leftInt, isLeftInt := left.(*obj.Number)
+	rigthInt, isRigthInt := rigth.(*obj.Number)
+	if isLeftInt && isRigthInt {
+		return arithmeticIntegers(operator, leftInt.Value, rigthInt.Value)
+	}
+
+	leftVal, isLeftNum := toFloat(left)
+	rigthVal, isRigthNum := toFloat(rigth)
+	if !isLeftNum || !isRigthNum {
+		return typeMismatchError(obj.Types[left.Type()], operator, obj.Types[rigth.Type()])
+	}
+
+	switch baseOperator(operator) {
+	case "+":
+		return obj.NewFloat(leftVal + rigthVal)
+	case "-":
+		return obj.NewFloat(leftVal - rigthVal)
+	case "*":
+		return obj.NewFloat(leftVal * rigthVal)
+	case "/":
+		if leftVal == 0 && rigthVal == 0 {
+			return divisionByZeroError()
+		}
+		return obj.NewFloat(leftVal / rigthVal)
+
+	default:
+		return unknownInfixOperator(obj.Types[left.Type()], operator, obj.Types[rigth.Type()])
+	}
+}
+
+// apply an arithmetic operator between two integers
+func arithmeticIntegers(operator string, left, rigth int) obj.Object {
+	switch baseOperator(operator) {
+	case "+":
+		return &obj.Number{Value: left + rigth}
+	case "-":
+		return &obj.Number{Value: left - rigth}
+	case "*":
+		return &obj.Number{Value: left * rigth}
+	case "/":
+		if rigth == 0 {
+			return divisionByZeroError()
+		}
+		return &obj.Number{Value: left / rigth}
+	case "%":
+		if rigth == 0 {
+			return divisionByZeroError()
+		}
+		return &obj.Number{Value: left % rigth}
+
+	default:
+		return unknownInfixOperator(obj.Types[obj.INTEGERS], operator, obj.Types[obj.INTEGERS])
+	}
+}
+
+// compare two numbers or two strings with the given operator, the numbers
+// are compared by value so an integer and a float can be equal
+func compareObjects(operator string, left, rigth obj.Object) obj.Object {
+	leftStr, isLeftStr := left.(*obj.String)
+	rigthStr, isRigthStr := rigth.(*obj.String)
+	if isLeftStr && isRigthStr {
+		return compareValues(operator, leftStr.Value, rigthStr.Value, left, rigth)
+	}
+
+	leftInt, isLeftInt := left.(*obj.Number)
+	rigthInt, isRigthInt := rigth.(*obj.Number)
+	if isLeftInt && isRigthInt {
+		return compareValues(operator, leftInt.Value, rigthInt.Value, left, rigth)
+	}
+
+	leftVal, isLeftNum := toFloat(left)
+	rigthVal, isRigthNum := toFloat(rigth)
+	if !isLeftNum || !isRigthNum {
+		return typeMismatchError(obj.Types[left.Type()], operator, obj.Types[rigth.Type()])
+	}
+
+	return compareValues(operator, leftVal, rigthVal, left, rigth)
+}
+
+// apply a comparison operator between two ordered values
+func compareValues[T int | float64 | string](operator string, leftVal, rigthVal T, left, rigth obj.Object) obj.Object {
+	switch operator {
+	case "==":
+		return toBooleanObject(leftVal == rigthVal)
+	case "!=":
+		return toBooleanObject(leftVal != rigthVal)
+	case ">":
+		return toBooleanObject(leftVal > rigthVal)
+	case "<":
+		return toBooleanObject(leftVal < rigthVal)
+	case ">=":
+		return toBooleanObject(leftVal >= rigthVal)
+	case "<=":
+		return toBooleanObject(leftVal <= rigthVal)
+
+	default:
+		return unknownInfixOperator(obj.Types[left.Type()], operator, obj.Types[rigth.Type()])
+	}
+}
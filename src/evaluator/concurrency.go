@@ -0,0 +1,50 @@
+package evaluator
+
+import (
+	b "aura/src/builtins"
+	obj "aura/src/object"
+)
+
+// girar is registered here because it needs to apply functions
+func init() {
+	b.ENV_BUILTINS["girar"] = spawn
+}
+
+// run the function with the given arguments in a new goroutine and return a
+// future with the result, the functions should share values only through
+// channels. An error in the function is returned by esperar
+func spawn(env *obj.Enviroment, args ...obj.Object) obj.Object {
+	if len(args) == 0 {
+		return newError("numero incorrecto de argumentos para girar, se recibieron 0, se requieren 1")
+	}
+
+	fn, isFn := args[0].(*obj.Def)
+	if !isFn {
+		return notAFunction(args[0].Inspect())
+	}
+
+	// the scopes visible to both goroutines need a lock
+	env.Synchronize()
+	fn.Env.Synchronize()
+
+	future := obj.NewFuture(env)
+	env.StartTask()
+	go func() {
+		defer env.EndTask()
+		future.Resolve(safeApplyFunction(fn, args[1:]...))
+	}()
+
+	return future
+}
+
+// apply the function recovering from any panic, used when the function runs
+// outside the goroutine of Run
+func safeApplyFunction(fn obj.Object, args ...obj.Object) (result obj.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = internalError(r)
+		}
+	}()
+
+	return applyFunction(nil, fn, args...)
+}
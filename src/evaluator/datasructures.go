@@ -2,8 +2,10 @@ package evaluator
 
 import (
 	"aura/src/ast"
+	b "aura/src/builtins"
 	obj "aura/src/object"
 	"strings"
+	"unicode/utf8"
 )
 
 // evaluate a map object
@@ -14,7 +16,14 @@ func evaluateMap(mapa *ast.MapExpression, env *obj.Enviroment) obj.Object {
 	// and add them to the hashMap
 	for _, keyVal := range mapa.Body {
 		key := Evaluate(keyVal.Key, env)
+		if isFailure(keyVal.Key, key, env) {
+			return key
+		}
+
 		val := Evaluate(keyVal.Value, env)
+		if isFailure(keyVal.Value, val, env) {
+			return val
+		}
 
 		if err := mapObj.SetValues(key, val); err != nil {
 			// duplicated keys
@@ -27,38 +36,132 @@ func evaluateMap(mapa *ast.MapExpression, env *obj.Enviroment) obj.Object {
 
 // evaluate an array expression
 func evaluateArray(arr *ast.Array, env *obj.Enviroment) obj.Object {
+	// we evaluate all the values in the array expression to add them to the list
+	// and stop in the first error
+	values, err := evaluateExpression(arr.Values, env)
+	if err != nil {
+		return err
+	}
+
+	return &obj.List{Values: values}
+}
+
+// evaluate a list comprehension like:
+//		lista[x * 2 por (x en valores) si (x > 0)]
+func evaluateListComprehension(comprehension *ast.ListComprehension, env *obj.Enviroment) obj.Object {
 	list := new(obj.List)
-	// we loop and evaluated all the values in the array expression to add them to the list
-	for _, val := range arr.Values {
-		list.Values = append(list.Values, Evaluate(val, env))
+	err := iterateComprehension(comprehension.Iteration, comprehension.Condition, env, func(iterEnv *obj.Enviroment) *obj.Error {
+		value := Evaluate(comprehension.Element, iterEnv)
+		if isFailure(comprehension.Element, value, iterEnv) {
+			return value.(*obj.Error)
+		}
+
+		list.Add(value)
+		return nil
+	})
+
+	if err != nil {
+		return err
 	}
 
 	return list
 }
 
+// evaluate a map comprehension like:
+//		mapa{x => x * 2 por (x en valores) si (x > 0)}
+func evaluateMapComprehension(comprehension *ast.MapComprehension, env *obj.Enviroment) obj.Object {
+	mapObj := &obj.Map{Store: map[string]obj.Object{}}
+	err := iterateComprehension(comprehension.Iteration, comprehension.Condition, env, func(iterEnv *obj.Enviroment) *obj.Error {
+		key := Evaluate(comprehension.Pair.Key, iterEnv)
+		if isFailure(comprehension.Pair.Key, key, iterEnv) {
+			return key.(*obj.Error)
+		}
+
+		value := Evaluate(comprehension.Pair.Value, iterEnv)
+		if isFailure(comprehension.Pair.Value, value, iterEnv) {
+			return value.(*obj.Error)
+		}
+
+		// unlike the map literals a repeated key just overrides the previous value
+		mapObj.UpdateKey(key, value)
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return mapObj
+}
+
+// iterate the range of a comprehension and call the given function with the
+// enviroment of each iteration where the condition is truthy
+func iterateComprehension(iteration, condition ast.Expression, env *obj.Enviroment, fn func(*obj.Enviroment) *obj.Error) *obj.Error {
+	rangeExp := iteration.(*ast.RangeExpression)
+	variable, isVar := rangeExp.Variable.(*ast.Identifier)
+	if !isVar {
+		return notAVariable(rangeExp.Variable.Str())
+	}
+
+	evaluated := Evaluate(rangeExp.Range, env)
+	if err, isErr := evaluated.(*obj.Error); isErr {
+		return err
+	}
+
+	values, isIterable := iterableValues(evaluated)
+	if !isIterable {
+		return notIterable(rangeExp.Range.Str())
+	}
+
+	for _, value := range values {
+		iterEnv := obj.NewEnviroment(env)
+		iterEnv.SetItem(variable.Value, value)
+
+		if condition != nil {
+			evaluated := Evaluate(condition, iterEnv)
+			if err, isErr := evaluated.(*obj.Error); isErr {
+				return err
+			}
+
+			if !isTruthy(evaluated) {
+				continue
+			}
+		}
+
+		if err := fn(iterEnv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // evaluate a list reassigment by index like:
 //		arr[0] = 2;
 func evaluateListReassigment(call *ast.CallList, list *obj.List, newVal ast.Expression, env *obj.Enviroment) obj.Object {
-	evaluated := Evaluate(call.Index, env)
-	num, isNum := evaluated.(*obj.Number)
-	if !isNum {
-		// the index is not a number
-		return newError("El indice debe ser un numero")
-	}
-
-	index, err := checkIndex(len(list.Values), num.Value)
+	index, err := evaluateIndex(call.Index, len(list.Values), env)
 	if err != nil {
 		return err
 	}
 
-	list.Values[index] = Evaluate(newVal, env)
+	if list.Frozen {
+		return frozenObject(obj.Types[obj.LIST])
+	}
+
+	value := Evaluate(newVal, env)
+	if isFailure(newVal, value, env) {
+		return value
+	}
+
+	list.Values[index] = value
 	return obj.SingletonNUll
 }
 
 // evaluate a HashMap reassigment
 func evaluateMapReassigment(hashMap *obj.Map, key obj.Object, value obj.Object) obj.Object {
-	// we dont care if the key doesnt exist
-	// we just add the key value pair to the map
+	// we dont care if the key doesnt exist we just add the key value pair
+	// to the map. the key is serialized once inside UpdateKey so an existing
+	// key of any type is updated and never duplicated
 	hashMap.UpdateKey(key, value)
 	return obj.SingletonNUll
 }
@@ -72,7 +175,7 @@ func evaluateReassigment(reassigment *ast.Reassignment, env *obj.Enviroment) obj
 	case *ast.ClassFieldCall:
 		evaluated := Evaluate(exp.Class, env)
 		if class, isClass := evaluated.(*obj.ClassInstance); isClass {
-			return evaluateFieldReassigment(exp, class, reassigment.NewVal)
+			return evaluateFieldReassigment(exp, class, reassigment.NewVal, env)
 		}
 
 		return notAClass(evaluated.Inspect())
@@ -86,7 +189,19 @@ func evaluateReassigment(reassigment *ast.Reassignment, env *obj.Enviroment) obj
 
 		if hashMap, isMap := evaluated.(*obj.Map); isMap {
 			key := Evaluate(exp.Index, env)
+			if _, isErr := key.(*obj.Error); isErr {
+				return key
+			}
+
 			newVal := Evaluate(reassigment.NewVal, env)
+			if isFailure(reassigment.NewVal, newVal, env) {
+				return newVal
+			}
+
+			if hashMap.Frozen {
+				return frozenObject(obj.Types[obj.DICT])
+			}
+
 			return evaluateMapReassigment(hashMap, key, newVal)
 		}
 
@@ -97,8 +212,127 @@ func evaluateReassigment(reassigment *ast.Reassignment, env *obj.Enviroment) obj
 	}
 }
 
+// evaluate an update like x++ or lista[0] += 2. the operation receives the
+// current value and the result is stored back in the variable, element or
+// field, the old value is never changed in place because the same number can
+// be shared by several variables, lists or maps
+func evaluateUpdate(target ast.Expression, env *obj.Enviroment, operation func(obj.Object) obj.Object) obj.Object {
+	switch target := target.(type) {
+	case *ast.Identifier:
+		value := Evaluate(target, env)
+		if isFailure(target, value, env) {
+			return value
+		}
+
+		result := operation(value)
+		if _, isErr := result.(*obj.Error); !isErr {
+			env.Reassign(target.Value, result)
+		}
+
+		return result
+
+	case *ast.ClassFieldCall:
+		evaluated := Evaluate(target.Class, env)
+		if _, isErr := evaluated.(*obj.Error); isErr {
+			return evaluated
+		}
+
+		class, isClass := evaluated.(*obj.ClassInstance)
+		if !isClass {
+			return notAClass(evaluated.Inspect())
+		}
+
+		if field, isIdent := target.Field.(*ast.Identifier); isIdent {
+			return evaluateUpdate(field, class.Env, operation)
+		}
+
+		if method, isCall := target.Field.(*ast.Call); isCall {
+			return updateValue(evaluateInstanceMethod(class, method, env), operation)
+		}
+
+		return updateValue(Evaluate(target.Field, class.Env), operation)
+
+	case *ast.CallList:
+		evaluated := Evaluate(target.ListIdent, env)
+		if isFailure(target.ListIdent, evaluated, env) {
+			return evaluated
+		}
+
+		switch container := evaluated.(type) {
+		case *obj.List:
+			index, err := evaluateIndex(target.Index, len(container.Values), env)
+			if err != nil {
+				return err
+			}
+
+			if container.Frozen {
+				return frozenObject(obj.Types[obj.LIST])
+			}
+
+			result := operation(container.Values[index])
+			if _, isErr := result.(*obj.Error); !isErr {
+				container.Values[index] = result
+			}
+
+			return result
+
+		case *obj.Map:
+			key := Evaluate(target.Index, env)
+			if isFailure(target.Index, key, env) {
+				return key
+			}
+
+			if container.Frozen {
+				return frozenObject(obj.Types[obj.DICT])
+			}
+
+			value, exists := container.Get(key)
+			if !exists {
+				value = obj.NullVAlue
+			}
+
+			result := operation(value)
+			if _, isErr := result.(*obj.Error); !isErr {
+				container.UpdateKey(key, result)
+			}
+
+			return result
+
+		case *obj.String:
+			return updateValue(evaluateStringCall(container, target, env), operation)
+
+		default:
+			return cannotBeIndexed(obj.Types[evaluated.Type()])
+		}
+	}
+
+	// any other expression like (1 + 2)++ is not stored anywhere
+	value := Evaluate(target, env)
+	if isFailure(target, value, env) {
+		return value
+	}
+
+	return operation(value)
+}
+
+// apply the operation of an update to a value that is not stored anywhere
+func updateValue(value obj.Object, operation func(obj.Object) obj.Object) obj.Object {
+	if _, isErr := value.(*obj.Error); isErr {
+		return value
+	}
+
+	return operation(value)
+}
+
 // evaluate a list method if the method is valid will be applied else will return an error
-func evaluateListMethods(list *obj.List, method *obj.Method) obj.Object {
+func evaluateListMethods(list *obj.List, method *obj.Method, env *obj.Enviroment) obj.Object {
+	switch method.MethodType {
+	case obj.POP, obj.APPEND, obj.REMOVE:
+		if list.Frozen {
+			return frozenObject(obj.Types[obj.LIST])
+		}
+	}
+
 	switch method.MethodType {
 	case obj.POP:
 		return list.Pop()
@@ -114,21 +348,56 @@ func evaluateListMethods(list *obj.List, method *obj.Method) obj.Object {
 	case obj.CONTAIS:
 		return list.Contains(method.Value)
 
+	case obj.INDEX:
+		return &obj.Number{Value: list.Index(method.Value)}
+
+	case obj.UNIQUE:
+		return list.Unique()
+
+	case obj.DEEPFLATTEN:
+		return list.DeepFlatten()
+
 	case obj.MAP:
 		fn := method.Value.(*obj.Def)
-		return list.Map(fn, applyFunction)
+		return list.Map(fn, applyFrom(env))
 
 	case obj.FOREACH:
 		fn := method.Value.(*obj.Def)
-		return list.ForEach(fn, applyFunction)
+		return list.ForEach(fn, applyFrom(env))
+
+	case obj.PARALLELMAP:
+		args := method.Value.(*obj.List).Values
+		return list.ParallelMap(args[0].(*obj.Def), args[1].(*obj.Number).Value, applyFrom(env))
 
 	case obj.FILTER:
 		fn := method.Value.(*obj.Def)
-		return list.Filter(fn, applyFunction, isTruthy)
+		return list.Filter(fn, applyFrom(env), isTruthy)
 
 	case obj.COUNT:
 		fn := method.Value.(*obj.Def)
-		return list.Count(fn, applyFunction, isTruthy)
+		return list.Count(fn, applyFrom(env), isTruthy)
+
+	case obj.TAKE:
+		return list.Slice(0, method.Value.(*obj.Number).Value)
+
+	case obj.SKIP:
+		return list.Slice(method.Value.(*obj.Number).Value, len(list.Values))
+
+	case obj.SUBLIST:
+		// the negative indexes count from the end like in the index expressions
+		bounds := method.Value.(*obj.List).Values
+		start, end := bounds[0].(*obj.Number).Value, bounds[1].(*obj.Number).Value
+		if start < 0 {
+			start += len(list.Values)
+		}
+		if end < 0 {
+			end += len(list.Values)
+		}
+
+		return list.Slice(start, end)
+
+	case obj.CHUNK:
+		return list.Chunk(method.Value.(*obj.Number).Value)
 
 	default:
 		return noSuchMethod(method.Inspect(), "list")
@@ -139,16 +408,14 @@ func evaluateListMethods(list *obj.List, method *obj.Method) obj.Object {
 func evaluateMapMethods(hashMap *obj.Map, method *obj.Method) obj.Object {
 	switch method.MethodType {
 	case obj.CONTAIS:
-		if hashMap.Get(method.Value.Inspect()) != obj.NullVAlue {
-			return obj.SingletonTRUE
-		}
-
-		return obj.SingletonFALSE
+		_, exists := hashMap.Get(method.Value)
+		return toBooleanObject(exists)
 
 	case obj.VALUES:
 		list := new(obj.List)
-		for _, val := range hashMap.Store {
-			list.Values = append(list.Values, val)
+		for _, key := range hashMap.Keys {
+			value, _ := hashMap.Get(key)
+			list.Values = append(list.Values, value)
 		}
 		return list
 
@@ -157,6 +424,68 @@ func evaluateMapMethods(hashMap *obj.Map, method *obj.Method) obj.Object {
 	}
 }
 
+// evaluate a set method if the method is valid will be applied else will return an error
+func evaluateSetMethods(set *obj.Set, method *obj.Method) obj.Object {
+	switch method.MethodType {
+	case obj.APPEND:
+		set.Add(method.Value)
+		return obj.SingletonNUll
+
+	case obj.DISCARD:
+		return toBooleanObject(set.Remove(method.Value))
+
+	case obj.CONTAIS:
+		return toBooleanObject(set.Contains(method.Value))
+
+	case obj.UNION:
+		return set.Union(method.Value.(*obj.Set))
+
+	case obj.INTERSECTION:
+		return set.Intersection(method.Value.(*obj.Set))
+
+	case obj.DIFFERENCE:
+		return set.Difference(method.Value.(*obj.Set))
+
+	default:
+		return noSuchMethod(method.Inspect(), "conjunto")
+	}
+}
+
+// evaluate a date method if the method is valid will be applied else will return an error
+func evaluateTimeMethod(date *obj.Time, method *obj.Method) obj.Object {
+	switch method.MethodType {
+	case obj.YEAR:
+		return &obj.Number{Value: date.Value.Year()}
+
+	case obj.MONTH:
+		return &obj.Number{Value: int(date.Value.Month())}
+
+	case obj.DAY:
+		return &obj.Number{Value: date.Value.Day()}
+
+	default:
+		return noSuchMethod(method.Inspect(), "fecha")
+	}
+}
+
+// evaluate a number method if the method is valid will be applied else will return an error
+func evaluateNumberMethod(number obj.Object, method *obj.Method) obj.Object {
+	switch method.MethodType {
+	case obj.ABS:
+		return b.Abs(number)
+
+	case obj.SIGN:
+		return b.Sign(number)
+
+	case obj.CLAMP:
+		limits := method.Value.(*obj.List).Values
+		return b.Clamp(number, limits[0], limits[1])
+
+	default:
+		return noSuchMethod(method.Inspect(), obj.Types[number.Type()])
+	}
+}
+
 // evaluate a string method if the method is valid will be applied else will return an error
 func evaluateStringMethod(str *obj.String, method *obj.Method) obj.Object {
 	switch method.MethodType {
@@ -169,7 +498,7 @@ func evaluateStringMethod(str *obj.String, method *obj.Method) obj.Object {
 		return str
 
 	case obj.CONTAIS:
-		val, isStr := method.Value.(*obj.String)
+		val, isStr := obj.AsText(method.Value)
 		if !isStr {
 			return newError("La funcion contiene solo puede recibir caracteres o cadenas")
 		}
@@ -186,15 +515,71 @@ func evaluateStringMethod(str *obj.String, method *obj.Method) obj.Object {
 		separator := method.Value.(*obj.String)
 		return str.Split(separator.Value)
 
+	case obj.STARTSWITH:
+		return toBooleanObject(strings.HasPrefix(str.Value, method.Value.(*obj.String).Value))
+
+	case obj.ENDSWITH:
+		return toBooleanObject(strings.HasSuffix(str.Value, method.Value.(*obj.String).Value))
+
+	case obj.PADLEFT, obj.PADRIGHT:
+		args := method.Value.(*obj.List).Values
+		width, pad := args[0].(*obj.Number).Value, args[1].(*obj.String).Value
+		return str.Pad(width, pad, method.MethodType == obj.PADLEFT)
+
+	case obj.SUBSTRING:
+		args := method.Value.(*obj.List).Values
+		end := utf8.RuneCountInString(str.Value)
+		if len(args) == 2 {
+			end = args[1].(*obj.Number).Value
+		}
+
+		return str.Substring(args[0].(*obj.Number).Value, end)
+
 	default:
 		return noSuchMethod(method.Inspect(), "texto")
 	}
 }
 
+// evaluate the method of a method expression, the methods are found in the
+// builtins before the variables so a variable can not hide them
+func evaluateMethodCall(method ast.Expression, env *obj.Enviroment) obj.Object {
+	call, isCall := method.(*ast.Call)
+	if !isCall {
+		return Evaluate(method, env)
+	}
+
+	ident, isIdent := call.Function.(*ast.Identifier)
+	if !isIdent {
+		return Evaluate(method, env)
+	}
+
+	builtin, exists := b.METHODS[ident.Value]
+	if !exists {
+		builtin, exists = findBuiltin(ident.Value, env)
+	}
+
+	if !exists {
+		return Evaluate(method, env)
+	}
+
+	args, err := evaluateExpression(call.Arguments, env)
+	if err != nil {
+		return err
+	}
+
+	return builtin.Fn(args...)
+}
+
 // evaluate a method expression
 func evaluateMethod(methodExp *ast.MethodExpression, env *obj.Enviroment) obj.Object {
 	evaluated := Evaluate(methodExp.Obj, env)
-	method, isMethod := Evaluate(methodExp.Method, env).(*obj.Method)
+	evaluatedMethod := evaluateMethodCall(methodExp.Method, env)
+	// the builtins validate the arguments of the method
+	if err, isErr := evaluatedMethod.(*obj.Error); isErr {
+		return err
+	}
+
+	method, isMethod := evaluatedMethod.(*obj.Method)
 	if !isMethod {
 		return notAMethod(methodExp.Method.Str())
 	}
@@ -203,7 +588,11 @@ func evaluateMethod(methodExp *ast.MethodExpression, env *obj.Enviroment) obj.Ob
 	switch data := evaluated.(type) {
 
 	case *obj.List:
-		return evaluateListMethods(data, method)
+		if method.MethodType == obj.PARALLELMAP {
+			// the scope of the caller is shared with the workers like in girar
+			env.Synchronize()
+		}
+		return evaluateListMethods(data, method, env)
 
 	case *obj.Map:
 		return evaluateMapMethods(data, method)
@@ -211,6 +600,26 @@ func evaluateMethod(methodExp *ast.MethodExpression, env *obj.Enviroment) obj.Ob
 	case *obj.String:
 		return evaluateStringMethod(data, method)
 
+	case *obj.Char:
+		// a character has the methods of the text with only that character
+		text, _ := obj.AsText(data)
+		return evaluateStringMethod(text, method)
+
+	case *obj.Time:
+		return evaluateTimeMethod(data, method)
+
+	case *obj.Number, *obj.Float:
+		return evaluateNumberMethod(data, method)
+
+	case *obj.Set:
+		return evaluateSetMethods(data, method)
+
+	case *obj.Future:
+		if method.MethodType != obj.WAIT {
+			return noSuchMethod(method.Inspect(), obj.Types[data.Type()])
+		}
+		return data.Wait()
+
 	default:
 		// the object has no methods
 		return noSuchMethod(methodExp.Method.Str(), methodExp.Obj.Str())
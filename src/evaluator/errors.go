@@ -3,6 +3,7 @@ package evaluator
 import (
 	obj "aura/src/object"
 	"fmt"
+	"strings"
 )
 
 // utils functions to return errors
@@ -12,6 +13,43 @@ func newError(message string) *obj.Error {
 	return &obj.Error{Message: message}
 }
 
+// return the message of the error with the line of the source where it
+// happened and a caret under the statement that failed like:
+//
+//	Error: Division entre 0
+//	  en linea 2, columna 1
+//	  x / 0;
+//	  ^
+func ErrorContext(err *obj.Error, source string) string {
+	message := err.Inspect()
+	lines := strings.Split(source, "\n")
+	if err.Line < 1 || err.Line > len(lines) {
+		return message
+	}
+
+	line := strings.TrimRight(lines[err.Line-1], "\r")
+	var caret strings.Builder
+	for idx, char := range []rune(line) {
+		if idx >= err.Column-1 {
+			break
+		}
+
+		// the tabs are kept so the caret is aligned with the source
+		if char == '\t' {
+			caret.WriteRune(char)
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+
+	return fmt.Sprintf("%s\n  en linea %d, columna %d\n  %s\n  %s^", message, err.Line, err.Column, line, caret.String())
+}
+
+// wrap a recovered panic in an error object
+func internalError(reason interface{}) *obj.Error {
+	return &obj.Error{Message: fmt.Sprintf("error interno del interprete: %v", reason)}
+}
+
 func typeMismatchError(left, operator, rigth string) *obj.Error {
 	return &obj.Error{
 		Message: fmt.Sprintf("Discrepancia de tipos: %s %s %s", left, operator, rigth),
@@ -34,15 +72,47 @@ func unknownInfixOperator(left, operator, rigth string) *obj.Error {
 	}
 }
 
+func undeclaredVariable(variable string) *obj.Error {
+	return &obj.Error{
+		Message: fmt.Sprintf("variable no declarada: %s", variable),
+	}
+}
+
+func constantReassignment(name string) *obj.Error {
+	return newError(fmt.Sprintf("no se puede reasignar una constante: %s", name))
+}
+
 func unknownIdentifier(identifier string) *obj.Error {
 	return &obj.Error{
 		Message: fmt.Sprintf("Identificador no encontrado: %s", identifier),
 	}
 }
 
-func notAFunction(identifier string) *obj.Error {
+func wrongNumberOfArgs(expected, found int) *obj.Error {
+	return &obj.Error{
+		Message: fmt.Sprintf("se esperaban %d argumentos pero se recibieron %d", expected, found),
+	}
+}
+
+func unknownArgument(name string) *obj.Error {
+	return newError(fmt.Sprintf("la funcion no tiene un parametro llamado %s", name))
+}
+
+func repeatedArgument(name string) *obj.Error {
+	return newError(fmt.Sprintf("el argumento %s se recibio mas de una vez", name))
+}
+
+func missingArgument(name string) *obj.Error {
+	return newError(fmt.Sprintf("falta el argumento %s", name))
+}
+
+func namedArgsNotSupported(value string) *obj.Error {
+	return newError(fmt.Sprintf("%s no acepta argumentos con nombre", value))
+}
+
+func notAFunction(value string) *obj.Error {
 	return &obj.Error{
-		Message: fmt.Sprintf("No es una funcion: %s", identifier),
+		Message: fmt.Sprintf("%s no es una funcion", value),
 	}
 }
 
@@ -79,10 +149,14 @@ func notAClass(ident string) *obj.Error {
 }
 
 func indexOutOfRange(found, actual int) *obj.Error {
-	msg := fmt.Sprintf("Indice fuera de rango indice: %d, longitud: %d", found, actual)
+	msg := fmt.Sprintf("indice %d fuera de rango (longitud %d)", found, actual)
 	return newError(msg)
 }
 
+func indexMustBeInteger(found string) *obj.Error {
+	return newError(fmt.Sprintf("el indice debe ser un entero, se obtuvo %s", found))
+}
+
 func noSuchField(class string, ident string) *obj.Error {
 	return newError(fmt.Sprintf("la clase %s no tiene la propiedad %s", class, ident))
 }
@@ -90,3 +164,20 @@ func noSuchField(class string, ident string) *obj.Error {
 func notAMethod(ident string) *obj.Error {
 	return &obj.Error{Message: fmt.Sprintf("%s no es un metodo", ident)}
 }
+
+func noMatchingPattern(value string) *obj.Error {
+	return newError(fmt.Sprintf("ningun patron coincide con %s", value))
+}
+
+// return a copy of the error used as a value in the excepto block
+func caughtError(err *obj.Error) *obj.Error {
+	return &obj.Error{Message: err.Message, Handled: true}
+}
+
+func frozenObject(objType string) *obj.Error {
+	return newError(fmt.Sprintf("no se puede modificar un valor de tipo %s congelado", objType))
+}
+
+func callDepthExceeded() *obj.Error {
+	return newError(fmt.Sprintf("se excedio el maximo de %d llamadas anidadas", MaxCallDepth))
+}
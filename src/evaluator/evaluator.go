@@ -4,11 +4,31 @@ import (
 	"aura/src/ast"
 	b "aura/src/builtins"
 	obj "aura/src/object"
-	"unicode/utf8"
+	"strings"
 )
 
+// represents the max number of nested function calls, a deeper recursion
+// returns an error instead of overflowing the stack
+const MaxCallDepth = 10000
+
+// evaluate the program recovering from any unexpected panic in the evaluator,
+// the panic is returned as an error so the host process never crashes
+func Run(program *ast.Program, env *obj.Enviroment) (evaluated obj.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			evaluated = internalError(r)
+		}
+	}()
+
+	return Evaluate(program, env)
+}
+
 // evlauate given nodes of the ast
 func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
+	if profile := env.Profile(); profile != nil {
+		profile.Count(baseNode)
+	}
+
 	switch node := baseNode.(type) {
 
 	case *ast.Program:
@@ -21,6 +41,16 @@ func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
 	case *ast.Array:
 		return evaluateArray(node, env)
 
+	case *ast.ListComprehension:
+		CheckIsNotNil(node.Element)
+		CheckIsNotNil(node.Iteration)
+		return evaluateListComprehension(node, env)
+
+	case *ast.MapComprehension:
+		CheckIsNotNil(node.Pair)
+		CheckIsNotNil(node.Iteration)
+		return evaluateMapComprehension(node, env)
+
 	case *ast.Integer:
 		CheckIsNotNil(node.Value)
 		return &obj.Number{Value: *node.Value}
@@ -37,16 +67,57 @@ func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
 		CheckIsNotNil(node.Rigth)
 		rigth := Evaluate(node.Rigth, env)
 		CheckIsNotNil(rigth)
+		if isFailure(node.Rigth, rigth, env) {
+			return rigth
+		}
+
 		return evaluatePrefixExpression(node.Operator, rigth)
 
 	case *ast.Infix:
 		CheckIsNotNil(node.Left)
 		CheckIsNotNil(node.Rigth)
+		switch node.Operator {
+		case "+=", "-=", "*=", "/=":
+			if err := reassignsConstant(node.Left, env); err != nil {
+				return err
+			}
+
+			return evaluateUpdate(node.Left, env, func(left obj.Object) obj.Object {
+				rigth := Evaluate(node.Rigth, env)
+				CheckIsNotNil(rigth)
+				if isFailure(node.Rigth, rigth, env) {
+					return rigth
+				}
+
+				return evaluateInfixExpression(node.Operator, left, rigth)
+			})
+		}
+
 		left := Evaluate(node.Left, env)
-		rigth := Evaluate(node.Rigth, env)
 		CheckIsNotNil(left)
+		if isFailure(node.Left, left, env) {
+			return left
+		}
+
+		// the rigth operand is not evaluated when the left one decides the
+		// result, so guards like i < largo(l) && l[i] > 0 never fail
+		if boolean, isBool := left.(*obj.Bool); isBool {
+			switch {
+			case node.Operator == "&&" && !boolean.Value:
+				return obj.SingletonFALSE
+
+			case node.Operator == "||" && boolean.Value:
+				return obj.SingletonTRUE
+			}
+		}
+
+		rigth := Evaluate(node.Rigth, env)
 		CheckIsNotNil(rigth)
-		return evaluateInfixExpression(node.Operator, left, rigth, env, node.Left)
+		if isFailure(node.Rigth, rigth, env) {
+			return rigth
+		}
+
+		return evaluateInfixExpression(node.Operator, left, rigth)
 
 	case *ast.Block:
 		return evaluateBLockStaments(node, env)
@@ -61,6 +132,10 @@ func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
 		CheckIsNotNil(node.ReturnValue)
 		value := Evaluate(node.ReturnValue, env)
 		CheckIsNotNil(value)
+		if isFailure(node.ReturnValue, value, env) {
+			return value
+		}
+
 		return &obj.Return{Value: value}
 
 	case *ast.For:
@@ -68,6 +143,10 @@ func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
 		CheckIsNotNil(node.Body)
 		return evaluateFor(node, env)
 
+	case *ast.ClassicFor:
+		CheckIsNotNil(node.Body)
+		return evaluateClassicFor(node, env)
+
 	case *ast.MethodExpression:
 		CheckIsNotNil(node.Method)
 		CheckIsNotNil(node.Obj)
@@ -84,8 +163,13 @@ func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
 	case *ast.Suffix:
 		CheckIsNotNil(node.Left)
 		CheckIsNotNil(node.Operator)
-		left := Evaluate(node.Left, env)
-		return evaluateSuffixExpression(node.Operator, left)
+		if err := reassignsConstant(node.Left, env); err != nil {
+			return err
+		}
+
+		return evaluateUpdate(node.Left, env, func(left obj.Object) obj.Object {
+			return evaluateSuffixExpression(node.Operator, left)
+		})
 
 	case *ast.Reassignment:
 		CheckIsNotNil(node.Identifier)
@@ -109,6 +193,14 @@ func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
 		CheckIsNotNil(node.Catch)
 		return evaluateTryExcept(node, env)
 
+	case *ast.TryValue:
+		CheckIsNotNil(node.Value)
+		return evaluateTryValue(node, env)
+
+	case *ast.Match:
+		CheckIsNotNil(node.Value)
+		return evaluateMatch(node, env)
+
 	case *ast.ArrowFunc:
 		CheckIsNotNil(node.Body)
 		return obj.NewDef(node.Body, env, node.Params...)
@@ -124,15 +216,46 @@ func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
 
 	case *ast.LetStatement:
 		CheckIsNotNil(node.Value)
-		value := Evaluate(node.Value, env)
 		CheckIsNotNil(node.Name)
+		if err := reassignsConstant(node.Name, env); err != nil {
+			return err
+		}
+
+		value := Evaluate(node.Value, env)
+		if isFailure(node.Value, value, env) {
+			return value
+		}
+
 		env.SetItem(node.Name.Value, value)
 		return obj.SingletonNUll
 
+	case *ast.ConstStatement:
+		CheckIsNotNil(node.Value)
+		CheckIsNotNil(node.Name)
+		if err := reassignsConstant(node.Name, env); err != nil {
+			return err
+		}
+
+		value := Evaluate(node.Value, env)
+		if isFailure(node.Value, value, env) {
+			return value
+		}
+
+		env.SetConstant(node.Name.Value, value)
+		return obj.SingletonNUll
+
 	case *ast.AssigmentExp:
 		CheckIsNotNil(node.Val)
-		value := Evaluate(node.Val, env)
 		CheckIsNotNil(node.Name)
+		if err := reassignsConstant(node.Name, env); err != nil {
+			return err
+		}
+
+		value := Evaluate(node.Val, env)
+		if isFailure(node.Val, value, env) {
+			return value
+		}
+
 		env.SetItem(node.Name.Value, value)
 		return value
 
@@ -165,24 +288,59 @@ func Evaluate(baseNode ast.ASTNode, env *obj.Enviroment) obj.Object {
 
 	case *ast.Call:
 		function := Evaluate(node.Function, env)
+		if _, isErr := function.(*obj.Error); isErr {
+			return function
+		}
+
 		CheckIsNotNil(node.Arguments)
-		args := evaluateExpression(node.Arguments, env)
+		args, err := evaluateExpression(node.Arguments, env)
+		if err != nil {
+			return err
+		}
+
 		CheckIsNotNil(function)
-		return applyFunction(function, args...)
+		args, err = bindNamedArguments(function, args, node, env)
+		if err != nil {
+			return err
+		}
+
+		return applyFunction(env, function, args...)
 
 	case *ast.StringLiteral:
 		return &obj.String{Value: node.Value}
 
+	case *ast.Char:
+		return &obj.Char{Value: node.Value}
+
+	case *ast.InterpolatedString:
+		return evaluateInterpolatedString(node, env)
+
 	default:
 		return obj.SingletonNUll
 	}
 }
 
-// generates a new function object
-func applyFunction(fn obj.Object, args ...obj.Object) obj.Object {
+// generates a new function object. env is the scope of the caller, used to
+// count the nested calls, nil when the function starts a new chain of calls
+// like the functions of girar
+func applyFunction(env *obj.Enviroment, fn obj.Object, args ...obj.Object) obj.Object {
 	switch function := fn.(type) {
 	case *obj.Def:
+		if len(args) != len(function.Parameters) {
+			return wrongNumberOfArgs(len(function.Parameters), len(args))
+		}
+
+		depth := 1
+		if env != nil {
+			depth = env.CallDepth() + 1
+		}
+
+		if depth > MaxCallDepth {
+			return callDepthExceeded()
+		}
+
 		extendedEnviron := extendFunctionEnviroment(function, args)
+		extendedEnviron.SetCallDepth(depth)
 		evaluated := Evaluate(function.Body, extendedEnviron)
 		CheckIsNotNil(evaluated)
 		return unwrapReturnValue(evaluated)
@@ -191,7 +349,15 @@ func applyFunction(fn obj.Object, args ...obj.Object) obj.Object {
 		return function.Fn(args...)
 
 	default:
-		return notAFunction(obj.Types[fn.Type()])
+		return notAFunction(fn.Inspect())
+	}
+}
+
+// return applyFunction bound to the scope of the caller, used by the methods
+// that call functions like map or filtrar
+func applyFrom(env *obj.Enviroment) func(obj.Object, ...obj.Object) obj.Object {
+	return func(fn obj.Object, args ...obj.Object) obj.Object {
+		return applyFunction(env, fn, args...)
 	}
 }
 
@@ -200,7 +366,22 @@ func unwrapReturnValue(object obj.Object) obj.Object {
 	if obj, isReturn := object.(*obj.Return); isReturn {
 		return obj.Value
 	}
-	return object
+	return loopControlOutsideLoop(object)
+}
+
+// only the loops can consume a romper or continuar, so if any of them
+// reaches a function or the program is changed to an error
+func loopControlOutsideLoop(object obj.Object) obj.Object {
+	switch object.(type) {
+	case *obj.BreakObj:
+		return newError("romper fuera de un bucle")
+
+	case *obj.ContinueObj:
+		return newError("continuar fuera de un bucle")
+
+	default:
+		return object
+	}
 }
 
 // create a new enviroment when a function is called
@@ -213,19 +394,88 @@ func extendFunctionEnviroment(fn *obj.Def, args []obj.Object) *obj.Enviroment {
 	return env
 }
 
+// return the arguments of the call in the order of the parameters of the function.
+// the named arguments are evaluated in the scope of the caller and placed in the
+// position of the parameter with the same name, after the positional ones
+func bindNamedArguments(fn obj.Object, args []obj.Object, call *ast.Call, env *obj.Enviroment) ([]obj.Object, *obj.Error) {
+	named := call.Named
+	if len(named) == 0 {
+		return args, nil
+	}
+
+	function, isDef := fn.(*obj.Def)
+	if !isDef {
+		return nil, namedArgsNotSupported(call.Function.Str())
+	}
+
+	if len(args) > len(function.Parameters) {
+		return nil, wrongNumberOfArgs(len(function.Parameters), len(args)+len(named))
+	}
+
+	bound := make([]obj.Object, len(function.Parameters))
+	copy(bound, args)
+	for _, arg := range named {
+		idx := parameterIndex(function.Parameters, arg.Name.Value)
+		if idx == -1 {
+			return nil, unknownArgument(arg.Name.Value)
+		}
+
+		if bound[idx] != nil {
+			return nil, repeatedArgument(arg.Name.Value)
+		}
+
+		value := Evaluate(arg.Value, env)
+		CheckIsNotNil(value)
+		if isFailure(arg.Value, value, env) {
+			return nil, value.(*obj.Error)
+		}
+		bound[idx] = value
+	}
+
+	for idx, value := range bound {
+		if value == nil {
+			return nil, missingArgument(function.Parameters[idx].Value)
+		}
+	}
+
+	return bound, nil
+}
+
+// return the index of the parameter with the given name or -1 if it does not exists
+func parameterIndex(parameters []*ast.Identifier, name string) int {
+	for idx, param := range parameters {
+		if param.Value == name {
+			return idx
+		}
+	}
+
+	return -1
+}
+
 // Evaluate a variable reassigment
 func evaluateVarReassigment(variable *ast.Identifier, newVal ast.Expression, env *obj.Enviroment) obj.Object {
 	_, exists := env.GetItem(variable.Value)
 	if !exists {
-		// the veriable does not exists
-		return unknownIdentifier(variable.Value)
+		// the variable must be declared with var or := before the reassigment
+		return undeclaredVariable(variable.Value)
 	}
 
-	env.Store[variable.Value] = Evaluate(newVal, env)
+	if err := reassignsConstant(variable, env); err != nil {
+		return err
+	}
+
+	evaluated := Evaluate(newVal, env)
+	if isFailure(newVal, evaluated, env) {
+		return evaluated
+	}
+
+	env.Reassign(variable.Value, evaluated)
 	return obj.SingletonNUll
 }
 
-// Evaluate a forloop expression
+// Evaluate a forloop expression. each iteration is evaluated in a new scope
+// with its own loop variable, so the closures created in the body capture the
+// value of their iteration and the declarations do not leak to the next one
 func evaluateFor(forLoop *ast.For, env *obj.Enviroment) obj.Object {
 	evaluated := Evaluate(forLoop.Condition, env)
 	if iter, isIter := evaluated.(*obj.Iterator); isIter {
@@ -233,21 +483,20 @@ func evaluateFor(forLoop *ast.For, env *obj.Enviroment) obj.Object {
 		// this does not fail because if not a variable the error will be handle by
 		// the evaluate iter function
 		val := forLoop.Condition.(*ast.RangeExpression).Variable.(*ast.Identifier).Value
-		for iter.Next() != nil {
-			evaluated = Evaluate(forLoop.Body, iter.Env)
+		for current := iter.Next(); current != nil; current = iter.Next() {
+			iterEnv := obj.NewEnviroment(env)
+			iterEnv.SetItem(val, current)
+
+			evaluated = Evaluate(forLoop.Body, iterEnv)
 			switch node := evaluated.(type) {
 			case *obj.Return:
 				return node
 
+			case *obj.Error:
+				return node
+
 			case *obj.BreakObj:
 				return obj.SingletonNUll
-
-			case *obj.ContinueObj:
-				iter.Env.SetItem(val, iter.Current)
-				continue
-
-			default:
-				iter.Env.SetItem(val, iter.Current)
 			}
 		}
 
@@ -261,6 +510,51 @@ func evaluateFor(forLoop *ast.For, env *obj.Enviroment) obj.Object {
 	return newError("Expression por invalida")
 }
 
+// Evaluate a classic forloop expression. the init statement is evaluated once in
+// a new enviroment, then the condition is checked before each iteration and the
+// post expression is evaluated after each iteration, even if the body continues.
+// the variables of the init statement are shared by all the iterations, so the
+// closures capture their final value
+func evaluateClassicFor(forLoop *ast.ClassicFor, env *obj.Enviroment) obj.Object {
+	loopEnv := obj.NewEnviroment(env)
+	if forLoop.Init != nil {
+		if err, isErr := Evaluate(forLoop.Init, loopEnv).(*obj.Error); isErr {
+			return err
+		}
+	}
+
+	for {
+		if forLoop.Condition != nil {
+			condition := Evaluate(forLoop.Condition, loopEnv)
+			if err, isErr := condition.(*obj.Error); isErr {
+				return err
+			}
+
+			if !isTruthy(condition) {
+				return obj.SingletonNUll
+			}
+		}
+
+		evaluated := Evaluate(forLoop.Body, loopEnv)
+		switch node := evaluated.(type) {
+		case *obj.Return:
+			return node
+
+		case *obj.Error:
+			return node
+
+		case *obj.BreakObj:
+			return obj.SingletonNUll
+		}
+
+		if forLoop.Post != nil {
+			if err, isErr := Evaluate(forLoop.Post, loopEnv).(*obj.Error); isErr {
+				return err
+			}
+		}
+	}
+}
+
 // evaluate an iter expression like:
 //		for(i in range(10)):
 func evaluateRange(rangeExpress *ast.RangeExpression, env *obj.Enviroment) obj.Object {
@@ -269,22 +563,24 @@ func evaluateRange(rangeExpress *ast.RangeExpression, env *obj.Enviroment) obj.O
 		return notAVariable(rangeExpress.Variable.Str())
 	}
 
-	if list, isList := Evaluate(rangeExpress.Range, env).(*obj.List); isList {
-		// if the iter is a list we make a iterable with the list
-		iter := obj.NewIterator(list.Values[0], list.Values, obj.NewEnviroment(env))
-		iter.Env.SetItem(val.Value, iter.Current)
-		return iter
+	evaluated := Evaluate(rangeExpress.Range, env)
+	if err, isErr := evaluated.(*obj.Error); isErr {
+		return err
+	}
+
+	// lists are iterated by value, strings by character and maps by key
+	values, isIterable := iterableValues(evaluated)
+	if !isIterable {
+		return notIterable(rangeExpress.Range.Str())
 	}
 
-	if str, isStr := Evaluate(rangeExpress.Range, env).(*obj.String); isStr {
-		// if the iter is a string we make a iterable with all the string characters
-		list := makeStringList(str.Value)
-		iter := obj.NewIterator(list[0], list, obj.NewEnviroment(env))
+	iter := obj.NewIterator(nil, values, obj.NewEnviroment(env))
+	if len(values) != 0 {
+		iter.Current = values[0]
 		iter.Env.SetItem(val.Value, iter.Current)
-		return iter
 	}
 
-	return notIterable(rangeExpress.Range.Str())
+	return iter
 }
 
 // extends the class enviroment with the methods and constructor arguments
@@ -301,7 +597,14 @@ func extendClassEnviroment(class *obj.Class, args []obj.Object, methods []*ast.C
 	return classEnv
 }
 
-// evaluate a call to a new class
+const (
+	constructorName = "iniciar" // name of the method called when a new instance is created
+	selfName        = "este"    // name of the instance inside its methods
+)
+
+// evaluate a call to a new class. the constructor params are assigned as
+// fields of the instance, then the iniciar method is called without arguments
+// if the class defines it, so it can validate or update the fields
 func evaluateClassCall(call *ast.ClassCall, env *obj.Enviroment) obj.Object {
 	evaluated := Evaluate(call.Class, env)
 	if _, isErr := evaluated.(*obj.Error); isErr {
@@ -309,9 +612,26 @@ func evaluateClassCall(call *ast.ClassCall, env *obj.Enviroment) obj.Object {
 	}
 
 	if class, isClass := evaluated.(*obj.Class); isClass {
-		args := evaluateExpression(call.Arguments, env)
+		args, err := evaluateExpression(call.Arguments, env)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != len(class.Params) {
+			return wrongNumberOfArgs(len(class.Params), len(args))
+		}
+
 		classEnv := extendClassEnviroment(class, args, class.Methods, env)
 		classInstance := obj.NewClassInstance(class.Name.Value, classEnv)
+		// the methods can use the instance itself like este.metodo()
+		classEnv.SetItem(selfName, classInstance)
+		if hasMethod(class, constructorName) {
+			constructor, _ := classEnv.GetItem(constructorName)
+			if err, isErr := applyFunction(env, constructor).(*obj.Error); isErr && !err.Handled {
+				return err
+			}
+		}
+
 		return classInstance
 	}
 
@@ -326,14 +646,45 @@ func evaluateClassFieldCall(call *ast.ClassFieldCall, env *obj.Enviroment) obj.O
 	}
 
 	if class, isClass := evaluated.(*obj.ClassInstance); isClass {
+		if method, isCall := call.Field.(*ast.Call); isCall {
+			return evaluateInstanceMethod(class, method, env)
+		}
+
 		return Evaluate(call.Field, class.Env)
 	}
 
 	return notAClass(evaluated.Inspect())
 }
 
-// evaluate a class field reassigment
-func evaluateFieldReassigment(call *ast.ClassFieldCall, class *obj.ClassInstance, newVal ast.Expression) obj.Object {
+// evaluate a method call like instancia.metodo(1, 2), the arguments are
+// evaluated in the scope of the caller and the method in the instance scope
+func evaluateInstanceMethod(instance *obj.ClassInstance, call *ast.Call, env *obj.Enviroment) obj.Object {
+	ident, isIdent := call.Function.(*ast.Identifier)
+	if !isIdent {
+		return Evaluate(call, instance.Env)
+	}
+
+	method, exists := instance.Env.GetItem(ident.Value)
+	if !exists {
+		return noSuchField(instance.Name, ident.Value)
+	}
+
+	args, err := evaluateExpression(call.Arguments, env)
+	if err != nil {
+		return err
+	}
+
+	args, err = bindNamedArguments(method, args, call, env)
+	if err != nil {
+		return err
+	}
+
+	return applyFunction(env, method, args...)
+}
+
+// evaluate a class field reassigment, the new value is evaluated in the scope
+// of the caller
+func evaluateFieldReassigment(call *ast.ClassFieldCall, class *obj.ClassInstance, newVal ast.Expression, env *obj.Enviroment) obj.Object {
 	ident, isIdent := call.Field.(*ast.Identifier)
 	if !isIdent {
 		return newError("Una funcion no puede ser reasignada")
@@ -344,7 +695,11 @@ func evaluateFieldReassigment(call *ast.ClassFieldCall, class *obj.ClassInstance
 		return noSuchField(class.Name, ident.Value)
 	}
 
-	evaluated := Evaluate(newVal, class.Env)
+	evaluated := Evaluate(newVal, env)
+	if isFailure(newVal, evaluated, env) {
+		return evaluated
+	}
+
 	class.Env.SetItem(ident.Value, evaluated)
 	return obj.SingletonNUll
 }
@@ -358,6 +713,10 @@ func CheckIsNotNil(val interface{}) {
 
 func evaluateTernaryIf(ternary *ast.TernaryIf, env *obj.Enviroment) obj.Object {
 	condition := Evaluate(ternary.Condition, env)
+	if isFailure(ternary.Condition, condition, env) {
+		return condition
+	}
+
 	if isTruthy(condition) {
 		return Evaluate(ternary.Consequence, env)
 	} else {
@@ -369,7 +728,7 @@ func evaluateTernaryIf(ternary *ast.TernaryIf, env *obj.Enviroment) obj.Object {
 func evaluateImportStatement(importStmt *ast.ImportStatement, env *obj.Enviroment) obj.Object {
 	evaluated := Evaluate(importStmt.Path, env)
 	if str, isStr := evaluated.(*obj.String); isStr {
-		fileEnv, err := importEnv(str.Value)
+		fileEnv, err := importEnv(str.Value, env)
 		if err != nil {
 			return err
 		}
@@ -383,40 +742,57 @@ func evaluateImportStatement(importStmt *ast.ImportStatement, env *obj.Enviromen
 
 // evluate all the statements in a bock expression
 func evaluateBLockStaments(block *ast.Block, env *obj.Enviroment) obj.Object {
-	var result obj.Object
+	// an empty block like si (x) {} evaluates to nulo
+	var result obj.Object = obj.SingletonNUll
 	for _, statement := range block.Staments {
+		trace(statement, env)
 		result = Evaluate(statement, env)
-		if result != nil && result.Type() == obj.RETURNTYPE || result.Type() == obj.ERROR {
-			return result
+		if result == nil {
+			continue
 		}
+		locateError(result, statement)
 
-		if result != nil && result.Type() == obj.BREAK || result.Type() == obj.CONTINUE {
+		// returns, errors, break and continue stop the rest of the block
+		switch result := result.(type) {
+		case *obj.Return, *obj.BreakObj, *obj.ContinueObj:
 			return result
+
+		case *obj.Error:
+			if !result.Handled {
+				return result
+			}
 		}
 	}
 
 	return result
 }
 
-// evaluate a slice of expressions
-func evaluateExpression(expressions []ast.Expression, env *obj.Enviroment) []obj.Object {
+// evaluate a slice of expressions, return the first error found if there is any
+func evaluateExpression(expressions []ast.Expression, env *obj.Enviroment) ([]obj.Object, *obj.Error) {
 	var result []obj.Object
 
 	for _, expression := range expressions {
 		evaluated := Evaluate(expression, env)
 		CheckIsNotNil(evaluated)
+		if isFailure(expression, evaluated, env) {
+			// we stop in the first error
+			return nil, evaluated.(*obj.Error)
+		}
+
 		result = append(result, evaluated)
 	}
 
-	return result
+	return result, nil
 }
 
-// check if given identifier exists in the enviroment
+// check if given identifier exists in the enviroment, the variables are looked up
+// first so they can shadow any builtin, then the builtins and at last the builtins
+// that use the enviroment
 func evaluateIdentifier(node *ast.Identifier, env *obj.Enviroment) obj.Object {
 	object, exists := env.GetItem(node.Value)
 	if !exists {
 		// check if the identifier is a builtin function
-		builtint, exists := b.BUILTINS[node.Value]
+		builtint, exists := findBuiltin(node.Value, env)
 		if !exists {
 			// the identifier doest not exists
 			return unknownIdentifier(node.Value)
@@ -428,17 +804,38 @@ func evaluateIdentifier(node *ast.Identifier, env *obj.Enviroment) obj.Object {
 	return object
 }
 
-// evaluate program node
+// return the builtin with the given name, the builtins that use the enviroment
+// are bound to the current scope
+func findBuiltin(name string, env *obj.Enviroment) (*obj.Builtin, bool) {
+	if builtin, exists := b.BUILTINS[name]; exists {
+		return builtin, true
+	}
+
+	envBuiltin, exists := b.ENV_BUILTINS[name]
+	if !exists {
+		return nil, false
+	}
+
+	return obj.NewBuiltin(func(args ...obj.Object) obj.Object {
+		return envBuiltin(env, args...)
+	}), true
+}
+
+// evaluate program node, all the statements are evaluated in order and the value
+// of the last one is returned, a return statement or an error stops the program
 func evaluateProgram(program *ast.Program, env *obj.Enviroment) obj.Object {
-	var result obj.Object
+	// an empty program evaluates to null
+	var result obj.Object = obj.SingletonNUll
 	for _, statement := range program.Staments {
+		trace(statement, env)
 		result = Evaluate(statement, env)
+		locateError(result, statement)
 
 		if returnObj, isReturn := result.(*obj.Return); isReturn {
 			return returnObj.Value
 		}
 
-		if err, isError := result.(*obj.Error); isError {
+		if err, isError := loopControlOutsideLoop(result).(*obj.Error); isError && !err.Handled {
 			return err
 		}
 	}
@@ -467,11 +864,18 @@ func evaluateWhileExpression(whileExpression *ast.While, env *obj.Enviroment) ob
 
 	// we loop an update the condition until the condition is not trythy
 	for isTruthy(condition) {
+		if err, isErr := condition.(*obj.Error); isErr {
+			return err
+		}
+
 		evaluated := Evaluate(whileExpression.Body, env)
 		switch node := evaluated.(type) {
 		case *obj.Return:
 			return node
 
+		case *obj.Error:
+			return node
+
 		case *obj.BreakObj:
 			return obj.SingletonNUll
 
@@ -497,8 +901,18 @@ func evaluateCallList(call *ast.CallList, env *obj.Enviroment) obj.Object {
 		return evaluateListCall(object, call, env)
 
 	case *obj.Map:
-		evaluated := Evaluate(call.Index, env)
-		return object.Get(evaluated.Inspect())
+		key := Evaluate(call.Index, env)
+		if isFailure(call.Index, key, env) {
+			return key
+		}
+
+		if value, exists := object.Get(key); exists {
+			return value
+		}
+
+		// reading a missing key is not an error, it is nulo like a key with
+		// a null value, contiene tells both cases apart
+		return obj.NullVAlue
 
 	case *obj.String:
 		return evaluateStringCall(object, call, env)
@@ -508,15 +922,9 @@ func evaluateCallList(call *ast.CallList, env *obj.Enviroment) obj.Object {
 	}
 }
 
+// evaluate a read by index of a list like lista[-1]
 func evaluateListCall(list *obj.List, call *ast.CallList, env *obj.Enviroment) obj.Object {
-	evaluated := Evaluate(call.Index, env)
-	num, isNumber := evaluated.(*obj.Number)
-	if !isNumber {
-		return newError("el indice debe ser un enetero")
-	}
-
-	length := len(list.Values)
-	index, err := checkIndex(length, num.Value)
+	index, err := evaluateIndex(call.Index, len(list.Values), env)
 	if err != nil {
 		return err
 	}
@@ -524,20 +932,31 @@ func evaluateListCall(list *obj.List, call *ast.CallList, env *obj.Enviroment) o
 	return list.Values[index]
 }
 
+// evaluate a read by index of a string, the index counts characters not bytes
 func evaluateStringCall(str *obj.String, call *ast.CallList, env *obj.Enviroment) obj.Object {
-	evaluated := Evaluate(call.Index, env)
-	num, isNumber := evaluated.(*obj.Number)
-	if !isNumber {
-		return newError("El indice debe ser un entero")
-	}
-
-	strLen := utf8.RuneCountInString(str.Value)
-	index, err := checkIndex(strLen, num.Value)
+	chars := []rune(str.Value)
+	index, err := evaluateIndex(call.Index, len(chars), env)
 	if err != nil {
 		return err
 	}
 
-	return &obj.String{Value: string(str.Value[index])}
+	return &obj.String{Value: string(chars[index])}
+}
+
+// evaluate a string with interpolated expressions, each expression is
+// converted to string and joined with the texts
+func evaluateInterpolatedString(str *ast.InterpolatedString, env *obj.Enviroment) obj.Object {
+	var buf strings.Builder
+	for _, part := range str.Parts {
+		evaluated := Evaluate(part, env)
+		if isFailure(part, evaluated, env) {
+			return evaluated
+		}
+
+		buf.WriteString(evaluated.Inspect())
+	}
+
+	return &obj.String{Value: buf.String()}
 }
 
 // evaluate an if expression
@@ -545,6 +964,9 @@ func evaluateIfExpression(ifExpression *ast.If, env *obj.Enviroment) obj.Object
 	CheckIsNotNil(ifExpression.Condition)
 	condition := Evaluate(ifExpression.Condition, env)
 	CheckIsNotNil(condition)
+	if isFailure(ifExpression.Condition, condition, env) {
+		return condition
+	}
 
 	if isTruthy(condition) {
 		// if the first condition is truthy we evaluate the consequence
@@ -563,16 +985,16 @@ func evaluateIfExpression(ifExpression *ast.If, env *obj.Enviroment) obj.Object
 // evaluate a try excpet expression
 func evaluateTryExcept(try *ast.TryExp, env *obj.Enviroment) obj.Object {
 	eval := Evaluate(try.Try, env)
-	if err, isErr := eval.(*obj.Error); isErr {
+	if err, isErr := eval.(*obj.Error); isErr && !err.Handled {
 		newEnv := obj.NewEnviroment(env)
-		newEnv.SetItem(try.Param.Value, err)
+		newEnv.SetItem(try.Param.Value, caughtError(err))
 		return Evaluate(try.Catch, newEnv)
 	}
 
 	if returnVal, isReturn := eval.(*obj.Return); isReturn {
-		if err, isErr := returnVal.Value.(*obj.Error); isErr {
+		if err, isErr := returnVal.Value.(*obj.Error); isErr && !err.Handled {
 			newEnv := obj.NewEnviroment(env)
-			newEnv.SetItem(try.Param.Value, err)
+			newEnv.SetItem(try.Param.Value, caughtError(err))
 			return Evaluate(try.Catch, newEnv)
 		}
 
@@ -582,6 +1004,21 @@ func evaluateTryExcept(try *ast.TryExp, env *obj.Enviroment) obj.Object {
 	return obj.SingletonNUll
 }
 
+// evaluate the expression form of try, the error is captured in the pair
+// [nulo, error] instead of stop the evaluation
+func evaluateTryValue(try *ast.TryValue, env *obj.Enviroment) obj.Object {
+	value := Evaluate(try.Value, env)
+	if err, isErr := value.(*obj.Error); isErr {
+		if !err.Handled {
+			err = caughtError(err)
+		}
+
+		return &obj.List{Values: []obj.Object{obj.SingletonNUll, err}}
+	}
+
+	return &obj.List{Values: []obj.Object{value, obj.SingletonNUll}}
+}
+
 // check that the current object is true or false
 func isTruthy(object obj.Object) bool {
 	switch {
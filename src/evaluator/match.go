@@ -0,0 +1,101 @@
+package evaluator
+
+import (
+	"aura/src/ast"
+	obj "aura/src/object"
+)
+
+// evaluate the body of the first arm whose pattern matches the value and whose
+// guard is truthy, the guard and the body are evaluated in a new scope with the
+// values bound by the pattern
+func evaluateMatch(match *ast.Match, env *obj.Enviroment) obj.Object {
+	value := Evaluate(match.Value, env)
+	if isFailure(match.Value, value, env) {
+		return value
+	}
+
+	for _, arm := range match.Arms {
+		armEnv := obj.NewEnviroment(env)
+		matched, err := matchPattern(arm.Pattern, value, armEnv)
+		if err != nil {
+			return err
+		}
+
+		if !matched {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Evaluate(arm.Guard, armEnv)
+			if isFailure(arm.Guard, guard, armEnv) {
+				return guard
+			}
+
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return Evaluate(arm.Body, armEnv)
+	}
+
+	return noMatchingPattern(value.Inspect())
+}
+
+// check if the value has the shape of the pattern, the identifiers in the
+// pattern are bound to the matched values in the enviroment
+func matchPattern(pattern ast.Expression, value obj.Object, env *obj.Enviroment) (bool, *obj.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		// _ matches anything without bind it
+		if pattern.Value != "_" {
+			env.SetItem(pattern.Value, value)
+		}
+		return true, nil
+
+	case *ast.ListPattern:
+		list, isList := value.(*obj.List)
+		if !isList || len(list.Values) != len(pattern.Elements) {
+			return false, nil
+		}
+
+		for idx, element := range pattern.Elements {
+			if matched, err := matchPattern(element, list.Values[idx], env); !matched || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case *ast.MapPattern:
+		hashMap, isMap := value.(*obj.Map)
+		if !isMap {
+			return false, nil
+		}
+
+		for _, pair := range pattern.Pairs {
+			key := Evaluate(pair.Key, env)
+			if err, isErr := key.(*obj.Error); isErr {
+				return false, err
+			}
+
+			mapValue, exists := hashMap.Store[hashMap.Serialize(key)]
+			if !exists {
+				return false, nil
+			}
+
+			if matched, err := matchPattern(pair.Value, mapValue, env); !matched || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	default:
+		// literals are compared with the same rules of ==
+		expected := Evaluate(pattern, env)
+		if err, isErr := expected.(*obj.Error); isErr {
+			return false, err
+		}
+
+		return evaluateInfixExpression("==", value, expected) == obj.SingletonTRUE, nil
+	}
+}
@@ -1,39 +1,36 @@
 package evaluator
 
 import (
-	"aura/src/ast"
 	obj "aura/src/object"
 	"fmt"
-	"reflect"
 )
 
 // evluate infix expressions between objects
-func evaluateInfixExpression(operator string, left obj.Object, right obj.Object, env *obj.Enviroment, leftNode ast.Expression) obj.Object {
+func evaluateInfixExpression(operator string, left obj.Object, right obj.Object) obj.Object {
 	switch {
 
 	case left.Type() == obj.INTEGERS && right.Type() == obj.INTEGERS:
 		return evaluateIntegerInfixExpression(operator, left, right)
 
-	case left.Type() == obj.FLOATING && right.Type() == obj.FLOATING:
+	case isNumeric(left) && isNumeric(right):
 		return evaluateFloatInfixExpression(operator, left, right)
 
-	case left.Type() == obj.FLOATING && right.Type() == obj.INTEGERS:
-		return evaluateLeftFloatInfixExp(operator, left, right)
-
-	case left.Type() == obj.INTEGERS && right.Type() == obj.FLOATING:
-		return evaluateRigthFloatInfixExp(operator, left, right, env, leftNode)
-
 	case left.Type() == obj.STRINGTYPE && right.Type() == obj.STRINGTYPE:
 		return evaluateStringInfixExpression(operator, left, right)
 
+	case isText(left) && isText(right):
+		return evaluateCharInfixExpression(operator, left, right)
+
 	case left.Type() == obj.BOOLEAN && right.Type() == obj.BOOLEAN:
 		return evaluateBoolInfixExpression(operator, left.(*obj.Bool), right.(*obj.Bool))
 
+	// only the numeric types are compared across types, any other pair of
+	// different types is never equal, e.g 5 == "5" is falso
 	case operator == "==":
-		return toBooleanObject(reflect.DeepEqual(left, right))
+		return toBooleanObject(obj.Equal(left, right))
 
 	case operator == "!=":
-		return toBooleanObject(!reflect.DeepEqual(left, right))
+		return toBooleanObject(!obj.Equal(left, right))
 
 	case left.Type() != right.Type():
 		return typeMismatchError(
@@ -52,159 +49,45 @@ func evaluateInfixExpression(operator string, left obj.Object, right obj.Object,
 
 }
 
-// evaluate bool infix expressions
-func evaluateBoolInfixExpression(operator string, left *obj.Bool, rigth *obj.Bool) obj.Object {
-	switch operator {
-
-	case "||":
-		return toBooleanObject(left.Value || rigth.Value)
-
-	case "&&":
-		return toBooleanObject(left.Value && rigth.Value)
-
-	case "==":
-		return toBooleanObject(left == rigth)
-
-	case "!=":
-		return toBooleanObject(left != rigth)
-
-	default:
-		return unknownInfixOperator(
-			obj.Types[left.Type()],
-			operator,
-			obj.Types[rigth.Type()],
-		)
-	}
+// check if the object is a text or a character
+func isText(object obj.Object) bool {
+	return object.Type() == obj.STRINGTYPE || object.Type() == obj.CHARTYPE
 }
 
-func evaluateLeftFloatInfixExp(operator string, left obj.Object, rigth obj.Object) obj.Object {
-	leftVal := left.(*obj.Float).Value
-	rigthVal := rigth.(*obj.Number).Value
+// evaluate infix expressions between a character and a text or another
+// character, the character behaves like a text with only that character
+func evaluateCharInfixExpression(operator string, left, rigth obj.Object) obj.Object {
+	leftStr := &obj.String{Value: left.Inspect()}
+	rigthStr := &obj.String{Value: rigth.Inspect()}
 
 	switch operator {
 	case "+":
-		return obj.NewFloat(leftVal + float64(rigthVal))
-	case "-":
-		return obj.NewFloat(leftVal - float64(rigthVal))
-	case "*":
-		return obj.NewFloat(leftVal * float64(rigthVal))
-	case "/":
-		if leftVal == 0 && rigthVal == 0 {
-			return divisionByZeroError()
-		}
-		return obj.NewFloat(leftVal / float64(rigthVal))
-
-	case "+=":
-		left.(*obj.Float).Value += float64(rigthVal)
-		return left
-
-	case "-=":
-		left.(*obj.Float).Value -= float64(rigthVal)
-		return left
-
-	case "/=":
-		if left.(*obj.Float).Value == 0 && rigthVal == 0 {
-			return divisionByZeroError()
-		}
-		left.(*obj.Float).Value /= float64(rigthVal)
-		return left
-
-	case "*=":
-		left.(*obj.Float).Value *= float64(rigthVal)
-		return left
+		return &obj.String{Value: leftStr.Value + rigthStr.Value}
 
-	case ">":
-		return toBooleanObject(leftVal > float64(rigthVal))
-	case "<":
-		return toBooleanObject(leftVal < float64(rigthVal))
-	case "==":
-		return toBooleanObject(leftVal == float64(rigthVal))
-	case "!=":
-		return toBooleanObject(leftVal != float64(rigthVal))
-	case ">=":
-		return toBooleanObject(leftVal >= float64(rigthVal))
-	case "<=":
-		return toBooleanObject(leftVal <= float64(rigthVal))
+	case "==", "!=", "<", ">", "<=", ">=":
+		return compareObjects(operator, leftStr, rigthStr)
 
 	default:
-		return unknownInfixOperator(
-			obj.Types[left.Type()],
-			operator,
-			obj.Types[rigth.Type()],
-		)
-	}
-}
-
-func isIdentifier(exp ast.Expression) (*ast.Identifier, *obj.Error) {
-	ident, isIdentifier := exp.(*ast.Identifier)
-	if !isIdentifier {
-		return nil, newError("no se puede user el /= sin una variable a la derecha si se usa un flotante a la izquierda")
+		return unknownInfixOperator(obj.Types[left.Type()], operator, obj.Types[rigth.Type()])
 	}
-	return ident, nil
 }
 
-func evaluateRigthFloatInfixExp(operator string, left obj.Object, rigth obj.Object, env *obj.Enviroment, leftNode ast.Expression) obj.Object {
-	leftVal := left.(*obj.Number).Value
-	rigthVal := rigth.(*obj.Float).Value
-	var variable string
-
-	if operator == "+=" || operator == "-=" || operator == "/=" || operator == "*=" {
-		ident, err := isIdentifier(leftNode)
-		if err != nil {
-			return err
-		}
-
-		variable = ident.Value
-	}
-
+// evaluate bool infix expressions. the booleans are not ordered, so a comparison
+// like verdadero > falso is an unknown operator instead of treating them as numbers
+func evaluateBoolInfixExpression(operator string, left *obj.Bool, rigth *obj.Bool) obj.Object {
 	switch operator {
-	case "+":
-		return obj.NewFloat(float64(leftVal) + rigthVal)
-	case "-":
-		return obj.NewFloat(float64(leftVal) - rigthVal)
-	case "*":
-		return obj.NewFloat(float64(leftVal) * rigthVal)
-	case "/":
-		if leftVal == 0 && rigthVal == 0 {
-			return divisionByZeroError()
-		}
-		return obj.NewFloat(float64(leftVal) / rigthVal)
 
-	case "+=":
-		env.SetItem(variable, obj.NewFloat(float64(leftVal)+rigthVal))
-		obj, _ := env.GetItem(variable)
-		return obj
+	case "||":
+		return toBooleanObject(left.Value || rigth.Value)
 
-	case "-=":
-		env.SetItem(variable, obj.NewFloat(float64(leftVal)-rigthVal))
-		obj, _ := env.GetItem(variable)
-		return obj
+	case "&&":
+		return toBooleanObject(left.Value && rigth.Value)
 
-	case "/=":
-		if leftVal == 0 && rigthVal == 0 {
-			return divisionByZeroError()
-		}
-		env.SetItem(variable, obj.NewFloat(float64(leftVal)/rigthVal))
-		obj, _ := env.GetItem(variable)
-		return obj
-
-	case "*=":
-		env.SetItem(variable, obj.NewFloat(float64(leftVal)*rigthVal))
-		obj, _ := env.GetItem(variable)
-		return obj
-
-	case ">":
-		return toBooleanObject(float64(leftVal) > rigthVal)
-	case "<":
-		return toBooleanObject(float64(leftVal) < rigthVal)
 	case "==":
-		return toBooleanObject(float64(leftVal) == rigthVal)
+		return toBooleanObject(left == rigth)
+
 	case "!=":
-		return toBooleanObject(float64(leftVal) != rigthVal)
-	case ">=":
-		return toBooleanObject(float64(leftVal) >= rigthVal)
-	case "<=":
-		return toBooleanObject(float64(leftVal) <= rigthVal)
+		return toBooleanObject(left != rigth)
 
 	default:
 		return unknownInfixOperator(
@@ -217,47 +100,27 @@ func evaluateRigthFloatInfixExp(operator string, left obj.Object, rigth obj.Obje
 
 // evaluate string infix expressions
 func evaluateStringInfixExpression(operator string, left obj.Object, rigth obj.Object) obj.Object {
-	leftVal := left.(*obj.String).Value
-	rigthVal := rigth.(*obj.String).Value
-
 	switch operator {
-	case "+":
-		return &obj.String{Value: leftVal + rigthVal}
-	case "+=":
-		left.(*obj.String).Value += rigthVal
-		return left
-	case "==":
-		return toBooleanObject(leftVal == rigthVal)
-	case "!=":
-		return toBooleanObject(leftVal != rigthVal)
-	case ">":
-		return toBooleanObject(leftVal > rigthVal)
-	case "<":
-		return toBooleanObject(leftVal < rigthVal)
+	case "+", "+=":
+		return &obj.String{Value: left.(*obj.String).Value + rigth.(*obj.String).Value}
 	default:
-		return unknownInfixOperator(
-			obj.Types[left.Type()],
-			operator,
-			obj.Types[rigth.Type()],
-		)
+		return compareObjects(operator, left, rigth)
 	}
 }
 
-// evaluate suffix expressions
+// evaluate suffix expressions, the result is a new number because the same
+// number can be shared by several variables, lists or maps
 func evaluateSuffixExpression(operator string, left obj.Object) obj.Object {
 	if num, isNumber := left.(*obj.Number); isNumber {
 		switch operator {
 		case "++":
-			num.Value++
-			return num
+			return &obj.Number{Value: num.Value + 1}
 
 		case "--":
-			num.Value--
-			return num
+			return &obj.Number{Value: num.Value - 1}
 
 		case "**":
-			num.Value *= num.Value
-			return num
+			return &obj.Number{Value: num.Value * num.Value}
 		default:
 			return &obj.Error{Message: "Operador desconocido para entero"}
 		}
@@ -266,120 +129,25 @@ func evaluateSuffixExpression(operator string, left obj.Object) obj.Object {
 	return newError(fmt.Sprintf("el operador %s solo puede ser aplicado en numeros", operator))
 }
 
+// evaluate infix expressions between a float and another number
 func evaluateFloatInfixExpression(operator string, left, rigth obj.Object) obj.Object {
-	leftVal := left.(*obj.Float).Value
-	rigthVal := rigth.(*obj.Float).Value
 	switch operator {
-	case "+":
-		return obj.NewFloat(leftVal + rigthVal)
-	case "-":
-		return obj.NewFloat(leftVal - rigthVal)
-	case "*":
-		return obj.NewFloat(leftVal * rigthVal)
-	case "/":
-		if leftVal == 0 && rigthVal == 0 {
-			return divisionByZeroError()
-		}
-		return obj.NewFloat(leftVal / rigthVal)
-	case "+=":
-		left.(*obj.Float).Value += rigthVal
-		return left
-
-	case "-=":
-		left.(*obj.Float).Value -= rigthVal
-		return left
-
-	case "/=":
-		if left.(*obj.Float).Value == 0 && rigthVal == 0 {
-			return divisionByZeroError()
-		}
-
-		left.(*obj.Float).Value /= rigthVal
-		return left
-
-	case "*=":
-		left.(*obj.Float).Value *= rigthVal
-		return left
-
-	case ">":
-		return toBooleanObject(leftVal > rigthVal)
-	case "<":
-		return toBooleanObject(leftVal < rigthVal)
-	case "==":
-		return toBooleanObject(leftVal == rigthVal)
-	case "!=":
-		return toBooleanObject(leftVal != rigthVal)
-	case ">=":
-		return toBooleanObject(leftVal >= rigthVal)
-	case "<=":
-		return toBooleanObject(leftVal <= rigthVal)
+	case "+", "-", "*", "/", "%", "+=", "-=", "*=", "/=":
+		return arithmeticObjects(operator, left, rigth)
 
 	default:
-		return unknownInfixOperator(
-			obj.Types[left.Type()],
-			operator,
-			obj.Types[rigth.Type()],
-		)
+		return compareObjects(operator, left, rigth)
 	}
 }
 
 // evluate infix integer expressions
 func evaluateIntegerInfixExpression(operator string, left, rigth obj.Object) obj.Object {
-	leftVal := left.(*obj.Number).Value
-	rigthVal := rigth.(*obj.Number).Value
-
 	switch operator {
-	case "+":
-		return &obj.Number{Value: leftVal + rigthVal}
-	case "-":
-		return &obj.Number{Value: leftVal - rigthVal}
-	case "*":
-		return &obj.Number{Value: leftVal * rigthVal}
-	case "/":
-		if leftVal == 0 && rigthVal == 0 {
-			return newError("division entre 0 ")
-		}
-		return &obj.Number{Value: leftVal / rigthVal}
-	case "%":
-		return &obj.Number{Value: leftVal % rigthVal}
-	case "+=":
-		left.(*obj.Number).Value += rigthVal
-		return left
-
-	case "-=":
-		left.(*obj.Number).Value -= rigthVal
-		return left
-
-	case "/=":
-		if left.(*obj.Number).Value == 0 && rigthVal == 0 {
-			return divisionByZeroError()
-		}
-		left.(*obj.Number).Value /= rigthVal
-		return left
-
-	case "*=":
-		left.(*obj.Number).Value *= rigthVal
-		return left
-
-	case ">":
-		return toBooleanObject(leftVal > rigthVal)
-	case "<":
-		return toBooleanObject(leftVal < rigthVal)
-	case "==":
-		return toBooleanObject(leftVal == rigthVal)
-	case "!=":
-		return toBooleanObject(leftVal != rigthVal)
-	case ">=":
-		return toBooleanObject(leftVal >= rigthVal)
-	case "<=":
-		return toBooleanObject(leftVal <= rigthVal)
+	case "+", "-", "*", "/", "%", "+=", "-=", "*=", "/=":
+		return arithmeticObjects(operator, left, rigth)
 
 	default:
-		return unknownInfixOperator(
-			obj.Types[left.Type()],
-			operator,
-			obj.Types[rigth.Type()],
-		)
+		return compareObjects(operator, left, rigth)
 	}
 }
 
@@ -387,12 +155,10 @@ func evaluateIntegerInfixExpression(operator string, left, rigth obj.Object) obj
 func evaluateMinusOperatorExpression(rigth obj.Object) obj.Object {
 	switch num := rigth.(type) {
 	case *obj.Number:
-		num.Value = -num.Value
-		return num
+		return &obj.Number{Value: -num.Value}
 
 	case *obj.Float:
-		num.Value = -num.Value
-		return num
+		return &obj.Float{Value: -num.Value}
 
 	default:
 		return unknownPrefixOperator("-", obj.Types[rigth.Type()])
@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"aura/src/ast"
 	"aura/src/lexer"
 	obj "aura/src/object"
 	"aura/src/parser"
@@ -20,8 +21,76 @@ func makeStringList(str string) []obj.Object {
 	return list
 }
 
+// check if the evaluated expression failed and the error must stop the evaluation.
+// an error stops the evaluation until is caught by excepto, the caught errors and
+// the ones created with the error builtin are values that can be stored, passed
+// and returned like any other object
+func isFailure(expression ast.Expression, evaluated obj.Object, env *obj.Enviroment) bool {
+	err, isErr := evaluated.(*obj.Error)
+	return isErr && !err.Handled && !isStoredValue(expression, env)
+}
+
+// check if the expression is a variable declared in the enviroment. errors
+// stored in variables, like the one bound by excepto, are values and does not
+// stop the evaluation
+func isStoredValue(expression ast.Expression, env *obj.Enviroment) bool {
+	ident, isIdent := expression.(*ast.Identifier)
+	if !isIdent {
+		return false
+	}
+
+	_, exists := env.GetItem(ident.Value)
+	return exists
+}
+
+// return the values to iterate of the given object if the object is iterable
+func iterableValues(iterable obj.Object) ([]obj.Object, bool) {
+	switch iterable := iterable.(type) {
+	case *obj.List:
+		return iterable.Values, true
+
+	case *obj.String:
+		return makeStringList(iterable.Value), true
+
+	case *obj.Char:
+		return makeStringList(iterable.Inspect()), true
+
+	case *obj.Map:
+		// iterating a map iterates its keys
+		return iterable.Keys, true
+
+	case *obj.Set:
+		return iterable.Values, true
+
+	default:
+		return nil, false
+	}
+}
+
+// validate that given path exists, have a file and the extension
+// is .aura
+func ValidatePath(path string) error {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("la ruta %s no existe", path)
+	}
+
+	if fileInfo.IsDir() {
+		return fmt.Errorf("la ruta indicada no contiene un archivo: %s", path)
+	}
+
+	if filepath.Ext(path) != ".aura" {
+		return fmt.Errorf(
+			"el archivo %s no es una archivo aura valido",
+			filepath.Base(path),
+		)
+	}
+
+	return nil
+}
+
 // import the enviroment of other file parsing and evaluating the other file
-func importEnv(path string) (*obj.Enviroment, *obj.Error) {
+func importEnv(path string, importer *obj.Enviroment) (*obj.Enviroment, *obj.Error) {
 	// check that path exists
 	fileInfo, err := os.Stat(path)
 	if err != nil {
@@ -51,6 +120,8 @@ func importEnv(path string) (*obj.Enviroment, *obj.Error) {
 	lexer := lexer.NewLexer(string(content))
 	parser := parser.NewParser(lexer)
 	env := obj.NewEnviroment(nil)
+	// the functions of the file can use the channels of the importer
+	env.ShareTasks(importer)
 	program := parser.ParseProgam()
 
 	// the file has syntax erros
@@ -69,7 +140,25 @@ func importEnv(path string) (*obj.Enviroment, *obj.Error) {
 	return nil, newError("La evaluacion fue nula")
 }
 
-// Check that given index is valid for a list call
+// evaluate the index of a list or string call and resolve it to a position
+// inside the given length, used by the reads and the writes so both follow
+// the same rules
+func evaluateIndex(index ast.Expression, length int, env *obj.Enviroment) (int, *obj.Error) {
+	evaluated := Evaluate(index, env)
+	if isFailure(index, evaluated, env) {
+		return 0, evaluated.(*obj.Error)
+	}
+
+	num, isNum := evaluated.(*obj.Number)
+	if !isNum {
+		return 0, indexMustBeInteger(obj.Types[evaluated.Type()])
+	}
+
+	return checkIndex(length, num.Value)
+}
+
+// Check that given index is valid for a list call, the negative indexes
+// count from the end
 func checkIndex(length int, index int) (int, *obj.Error) {
 	if index >= length {
 		return 0, indexOutOfRange(index, length)
@@ -85,3 +174,44 @@ func checkIndex(length int, index int) (int, *obj.Error) {
 
 	return index, nil
 }
+
+// call the trace hook of the enviroment before evaluate the statement
+func trace(statement ast.Stmt, env *obj.Enviroment) {
+	if hook := env.Trace(); hook != nil {
+		hook(statement, env)
+	}
+}
+
+// set the position of the statement in an unhandled error that does not have
+// one yet, so the error points to the innermost statement that failed
+func locateError(result obj.Object, statement ast.Stmt) {
+	err, isErr := result.(*obj.Error)
+	if !isErr || err.Handled || err.Line != 0 {
+		return
+	}
+
+	if node, hasPosition := statement.(interface{ Position() (int, int) }); hasPosition {
+		err.Line, err.Column = node.Position()
+	}
+}
+
+// check if the class defines a method with the given name
+func hasMethod(class *obj.Class, name string) bool {
+	for _, method := range class.Methods {
+		if method.Name.Value == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// return an error if the expression is a variable declared with constante,
+// used before the operators that change the variable like x += 1 or x++
+func reassignsConstant(target ast.Expression, env *obj.Enviroment) *obj.Error {
+	if ident, isIdent := target.(*ast.Identifier); isIdent && env.IsConstant(ident.Value) {
+		return constantReassignment(ident.Value)
+	}
+
+	return nil
+}
@@ -1,13 +1,22 @@
 package lexer
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"regexp"
+	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
+// literal of the illegal token returned when a block comment is not closed
+const unterminatedComment = "comentario sin cerrar"
+
+// literal of the illegal token returned when a character literal is not closed
+const unterminatedChar = "caracter sin cerrar"
+
 var (
-	charRegex    = regexp.MustCompile(`^[a-záéíóúA-ZÁÉÍÓÚñÑ_]$`)
 	numRegex     = regexp.MustCompile(`^\d$`)
 	wSpaceRegex  = regexp.MustCompile(`^\s$`)
 	newLineRegex = regexp.MustCompile(`^\n$`)
@@ -15,28 +24,126 @@ var (
 
 // Represents the lexer of the programming lenguage
 type Lexer struct {
-	source        string // represents the source code
-	character     string // represents the current character
-	read_position int    // represents the next position in the current source
-	position      int    // represents the current postion in source read
+	reader        *bufio.Reader // represents the reader of the source code
+	err           error         // represents a posible error while reading the source
+	character     string        // represents the current character
+	peek          string        // represents the next character
+	peekSize      int           // represents the size in bytes of the next character
+	read_position int           // represents the byte offset of the next character in the source
+	position      int           // represents the byte offset of the current character in the source
+	line          int           // represents the line of the current character
+	column        int           // represents the column of the current character
 }
 
 // create a new lexer
 func NewLexer(source string) *Lexer {
+	return NewLexerAt(source, 0, 1, 1)
+}
+
+// create a new lexer that starts reading the source at the given byte offset.
+// line and column are the position of the character at that offset, this allows
+// to lex again only a part of the source after an edit
+func NewLexerAt(source string, offset, line, column int) *Lexer {
+	if offset < 0 {
+		offset = 0
+	}
+
+	if offset > len(source) {
+		offset = len(source)
+	}
+
+	// we dont allow to start in the middle of a multibyte character
+	for offset < len(source) && !utf8.RuneStart(source[offset]) {
+		offset++
+	}
+
+	return newLexer(strings.NewReader(source[offset:]), offset, line, column)
+}
+
+// create a new lexer that reads the source code from the given reader.
+// the source is read incrementally so big files dont need to be loaded in memory
+func NewLexerFromReader(reader io.Reader) *Lexer {
+	return newLexer(reader, 0, 1, 1)
+}
+
+// create a new lexer with the position of the first character in the reader
+func newLexer(reader io.Reader, offset, line, column int) *Lexer {
 	lexer := &Lexer{
-		source:        source,
+		reader:        bufio.NewReader(reader),
 		character:     "",
-		read_position: 0,
-		position:      0,
+		read_position: offset,
+		position:      offset,
+		line:          line,
+		column:        column - 1,
 	}
 
+	lexer.readPeek()
 	lexer.readCharacter()
 	return lexer
 }
 
-// read next token and assing a token type to the token
+// create a new lexer that starts reading the source at the begining of the given line.
+// if the line does not exists the lexer will start at the end of the source
+func NewLexerFromLine(source string, line int) *Lexer {
+	offset := 0
+	for current := 1; current < line; current++ {
+		next := strings.IndexByte(source[offset:], '\n')
+		if next == -1 {
+			return NewLexerAt(source, len(source), current, len(source)-offset+1)
+		}
+
+		offset += next + 1
+	}
+
+	return NewLexerAt(source, offset, line, 1)
+}
+
+// return the byte offset of the current character in the source
+func (l *Lexer) Offset() int {
+	return l.position
+}
+
+// return the line of the current character
+func (l *Lexer) Line() int {
+	return l.line
+}
+
+// return the column of the current character
+func (l *Lexer) Column() int {
+	return l.column
+}
+
+// return the error found while reading the source if there is any.
+// when the source can not be read the lexer behaves as if it reached the end of the file
+func (l *Lexer) Err() error {
+	return l.err
+}
+
+// read next token and assing a token type to the token, the token has the
+// line and column of its first character
 func (l *Lexer) NextToken() *Token {
 	l.skipWhiteSpaces()
+	for l.isCommentStart() {
+		if l.isBlockCommentStart() {
+			line, column := l.line, l.column
+			if !l.skipBlockComment() {
+				return &Token{Token_type: ILLEGAL, Literal: unterminatedComment, Line: line, Column: column}
+			}
+		} else {
+			l.skipComment()
+			l.readCharacter()
+		}
+		l.skipWhiteSpaces()
+	}
+
+	line, column := l.line, l.column
+	token := l.readToken()
+	token.Line, token.Column = line, column
+	return token
+}
+
+// read the token that starts in the current character
+func (l *Lexer) readToken() *Token {
 	if l.isLetter(l.character) {
 		literal := l.readIdentifier()
 		token_type := LookUpTokenType(literal)
@@ -44,19 +151,23 @@ func (l *Lexer) NextToken() *Token {
 
 	} else if l.isNumber(l.character) {
 		literal := l.readNumber()
+		isFloat := false
 		if l.character == "." {
 			literal += l.character
 			l.readCharacter()
 			literal += l.readNumber()
+			isFloat = true
+		}
+
+		if l.character == "e" || l.character == "E" {
+			literal += l.readExponent()
+			isFloat = true
+		}
+
+		if isFloat {
 			return NewToken(FLOAT, literal)
 		}
 		return NewToken(INT, literal)
-	} else if l.character == "/" {
-		if l.peekCharacter() == "/" {
-			l.skipComment()
-			l.readCharacter()
-			return l.NextToken()
-		}
 	}
 
 	var token *Token
@@ -171,13 +282,16 @@ func (l *Lexer) NextToken() *Token {
 			token = NewToken(NOT, l.character)
 		}
 
-	case `"`:
-		literal := l.readString()
-		token = NewToken(STRING, literal)
-
 	case "'":
-		literal := l.readString()
-		token = NewToken(STRING, literal)
+		token = l.readSingleQuoted()
+
+	case `"`:
+		literal, interpolated := l.readString()
+		if interpolated {
+			token = NewToken(TEMPLATE, literal)
+		} else {
+			token = NewToken(STRING, unescape(literal))
+		}
 
 	default:
 		token = NewToken(ILLEGAL, l.character)
@@ -187,9 +301,21 @@ func (l *Lexer) NextToken() *Token {
 	return token
 }
 
-// check if current character is letter
+// check if current character is letter. any unicode letter is valid
+// so identifiers like año or niño are allowed
 func (l *Lexer) isLetter(char string) bool {
-	return charRegex.MatchString(char)
+	if char == "_" {
+		return true
+	}
+
+	r, _ := utf8.DecodeRuneInString(char)
+	return char != "" && unicode.IsLetter(r)
+}
+
+// check if current character can be part of an identifier after the first character
+func (l *Lexer) isIdentifierCharacter(char string) bool {
+	r, _ := utf8.DecodeRuneInString(char)
+	return l.isLetter(char) || (char != "" && unicode.IsDigit(r))
 }
 
 // check if current character is number
@@ -207,61 +333,236 @@ func (l *Lexer) makeTwoCharacterToken(tokenType TokenType) *Token {
 
 // read the current character and advance to  the next character
 func (l *Lexer) readCharacter() {
-	if l.read_position >= utf8.RuneCountInString(l.source) {
-		l.character = ""
+	if l.character == "\n" {
+		l.line++
+		l.column = 1
 	} else {
-		l.character = string([]rune(l.source)[l.read_position])
+		l.column++
 	}
 
+	l.character = l.peek
 	l.position = l.read_position
-	l.read_position++
+	l.read_position += l.peekSize
+	l.readPeek()
+}
+
+// read the next character from the reader. the bufio reader takes care
+// of the multibyte characters that are splitted between reads
+func (l *Lexer) readPeek() {
+	char, size, err := l.reader.ReadRune()
+	if err != nil {
+		if err != io.EOF {
+			l.err = err
+		}
+
+		l.peek, l.peekSize = "", 0
+		return
+	}
+
+	l.peek, l.peekSize = string(char), size
+}
+
+// check if the current character starts a comment like //, # or /*
+func (l *Lexer) isCommentStart() bool {
+	return l.character == "#" || (l.character == "/" && l.peekCharacter() == "/") || l.isBlockCommentStart()
+}
+
+// check if the current character starts a block comment like /* comentario */
+func (l *Lexer) isBlockCommentStart() bool {
+	return l.character == "/" && l.peekCharacter() == "*"
+}
+
+// skip a block comment until the closing */, the block comments can not be
+// nested and a /* inside a string is not a comment. return false if the
+// source ends before the comment is closed
+func (l *Lexer) skipBlockComment() bool {
+	// skip the opening /*
+	l.readCharacter()
+	l.readCharacter()
+
+	for l.character != "" {
+		if l.character == "*" && l.peekCharacter() == "/" {
+			l.readCharacter()
+			l.readCharacter()
+			return true
+		}
+
+		l.readCharacter()
+	}
+
+	return false
 }
 
 // reads the next character until it reaches a newline
 func (l *Lexer) skipComment() {
-	for !newLineRegex.MatchString(l.peekCharacter()) {
+	// a comment in the last line ends with the source
+	for l.peekCharacter() != "" && !newLineRegex.MatchString(l.peekCharacter()) {
 		l.readCharacter()
 	}
 }
 
 // read character sequence
 func (l *Lexer) readIdentifier() string {
-	initialPosition := l.position
-	for l.isLetter(l.character) || l.isNumber(l.character) {
+	var literal strings.Builder
+	for l.isIdentifierCharacter(l.character) {
+		literal.WriteString(l.character)
 		l.readCharacter()
 	}
 
-	return l.source[initialPosition:l.position]
+	return literal.String()
 }
 
-// read a sequence of digits characters
+// read a sequence of digits characters, the digits can be separated with
+// underscores like 1_000 and the parser checks that they are well placed
 func (l *Lexer) readNumber() string {
-	initialPosition := l.position
-	for l.isNumber(l.character) {
+	var literal strings.Builder
+	for l.isNumber(l.character) || l.character == "_" {
+		literal.WriteString(l.character)
+		l.readCharacter()
+	}
+	return literal.String()
+}
+
+// read the exponent of a number in scientific notation like e3 or E-4, the
+// parser checks that the exponent has digits
+func (l *Lexer) readExponent() string {
+	exponent := l.character
+	l.readCharacter()
+	if l.character == "+" || l.character == "-" {
+		exponent += l.character
 		l.readCharacter()
 	}
-	return l.source[initialPosition:l.position]
+
+	return exponent + l.readNumber()
+}
+
+// read a character literal like 'a' or '\n', the literals between single
+// quotes with more or less than one character are illegal tokens
+func (l *Lexer) readSingleQuoted() *Token {
+	literal, _ := l.readString()
+	if l.character == "" {
+		return NewToken(ILLEGAL, unterminatedChar)
+	}
+
+	value := unescape(literal)
+	if utf8.RuneCountInString(value) != 1 {
+		return NewToken(ILLEGAL, fmt.Sprintf("caracter no valido '%s', se esperaba un solo caracter", literal))
+	}
+
+	return NewToken(CHAR, value)
 }
 
-// read string will read a string literal
-func (l *Lexer) readString() string {
+// read string will read a string literal, also return if the string has
+// expressions to interpolate like "hola ${nombre}". the string ends with the
+// same quote that starts it, so "it's" is a valid string
+func (l *Lexer) readString() (string, bool) {
+	quote := l.character
 	l.readCharacter()
-	initialPosition := l.position
+	var literal strings.Builder
+	interpolated := false
+
+	for l.character != quote && l.character != "" {
+		if l.character == `\` && l.peekCharacter() != "" {
+			// the escape sequences like \" or \${ are kept in the literal, but the
+			// escaped character does not end the string or start an interpolation
+			literal.WriteString(l.character)
+			l.readCharacter()
+		} else if l.character == "$" && l.peekCharacter() == "{" {
+			interpolated = true
+			l.readInterpolation(&literal)
+		}
 
-	for l.character != `"` && l.character != "'" && l.read_position <= utf8.RuneCountInString(l.source) {
+		literal.WriteString(l.character)
 		l.readCharacter()
 	}
 
-	return l.source[initialPosition:l.position]
+	return literal.String(), interpolated
 }
 
-// return the next of character of the current string
-func (l *Lexer) peekCharacter() string {
-	if l.read_position >= utf8.RuneCountInString(l.source) {
-		return ""
+// return the character represented by an escape sequence like \n, the
+// character is the one after the backslash
+func EscapeSequence(char byte) (string, bool) {
+	switch char {
+	case 'n':
+		return "\n", true
+	case 't':
+		return "\t", true
+	case 'r':
+		return "\r", true
+	case '"', '\'', '\\':
+		return string(char), true
+	default:
+		return "", false
 	}
+}
+
+// replace the escape sequences of the literal with the characters they
+// represent. an escape sequence that is not valid like \q is kept as it is
+func unescape(literal string) string {
+	var out strings.Builder
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '\\' || i+1 == len(literal) {
+			out.WriteByte(literal[i])
+			continue
+		}
 
-	return string(l.source[l.read_position])
+		if strings.HasPrefix(literal[i:], `\${`) {
+			// escaped interpolation, we keep the literal
+			out.WriteString("${")
+			i += 2
+			continue
+		}
+
+		if char, isEscape := EscapeSequence(literal[i+1]); isEscape {
+			out.WriteString(char)
+			i++
+			continue
+		}
+
+		out.WriteByte(literal[i])
+	}
+
+	return out.String()
+}
+
+// read an interpolated expression until the closing brace. the expression
+// can contain braces and strings, so the closing brace is the matching one
+func (l *Lexer) readInterpolation(literal *strings.Builder) {
+	depth := 0
+	quote := ""
+	for l.character != "" {
+		switch {
+		case quote != "" && l.character == `\`:
+			// the escaped character can not close the string
+			literal.WriteString(l.character)
+			l.readCharacter()
+
+		case quote != "":
+			if l.character == quote {
+				quote = ""
+			}
+
+		case l.character == `"` || l.character == "'":
+			quote = l.character
+
+		case l.character == "{":
+			depth++
+
+		case l.character == "}":
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+
+		literal.WriteString(l.character)
+		l.readCharacter()
+	}
+}
+
+// return the next of character of the current string
+func (l *Lexer) peekCharacter() string {
+	return l.peek
 }
 
 // skip all whitespaces
@@ -11,10 +11,12 @@ const (
 	ASSING
 	ARROW
 	BAR
+	CHAR
 	CLASS
 	COLON
 	COLONASSING
 	COMMA
+	CONST
 	DATASTRCUT
 	DIVISION
 	DIVASSING
@@ -70,6 +72,8 @@ const (
 	CONTINUE
 	BREAK
 	QUESTION
+	TEMPLATE
+	MATCH
 )
 
 // String representation of all tokens
@@ -115,6 +119,7 @@ var Tokens = [...]string{
 	SEMICOLON:   ";",
 	TIMES:       "*",
 	STRING:      `"`,
+	CHAR:        "'",
 	THROW:       "lanzar",
 	TRUE:        "verdaro",
 	WHILE:       "mientras",
@@ -127,15 +132,20 @@ var Tokens = [...]string{
 	DIVASSING:   "/=",
 	EXPONENT:    "**",
 	IMPORT:      "importar",
+	CONST:       "constante",
 	CONTINUE:    "continuear",
 	BREAK:       "romper",
 	QUESTION:    "?",
+	TEMPLATE:    "${",
+	MATCH:       "coincidir",
 }
 
 // Represents a Token in the programmig lenguage
 type Token struct {
 	Token_type TokenType // represents the type of the token
 	Literal    string    // represents the literal of the token
+	Line       int       // represents the line where the token starts
+	Column     int       // represents the column in runes where the token starts
 }
 
 // Generate a new Token instance
@@ -157,6 +167,7 @@ func LookUpTokenType(literal string) TokenType {
 		"si":        IF,
 		"si_no":     ELSE,
 		"var":       LET,
+		"constante": CONST,
 		"verdadero": TRUE,
 		"en":        IN,
 		"mientras":  WHILE,
@@ -172,6 +183,7 @@ func LookUpTokenType(literal string) TokenType {
 		"lanzar":    THROW,
 		"continuar": CONTINUE,
 		"romper":    BREAK,
+		"coincidir": MATCH,
 	}
 
 	if TokenType, exists := keywords[literal]; exists {
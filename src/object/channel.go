@@ -0,0 +1,55 @@
+package object
+
+// represents a channel to send values between functions running at the same time
+type Channel struct {
+	size      int        // represents the values that can be sent without a function receiving them
+	values    []Object   // represents the values sent and not received yet
+	receivers int        // represents the functions waiting to receive a value
+	tasks     *scheduler // represents the functions of the interpreter that created the channel
+}
+
+// return a new channel instance for the interpreter of the enviroment, with
+// size 0 the channel is unbuffered
+func NewChannel(size int, env *Enviroment) *Channel {
+	return &Channel{size: size, tasks: env.tasks}
+}
+
+// send the value, waits until other function is receiving if the channel has
+// no space. returns an error instead of waiting forever when no function can
+// receive it
+func (c *Channel) Send(value Object) Object {
+	c.tasks.lock.Lock()
+	defer c.tasks.lock.Unlock()
+
+	if !c.tasks.wait(func() bool { return len(c.values) < c.size+c.receivers }) {
+		return deadlockError("enviar")
+	}
+
+	c.values = append(c.values, value)
+	c.tasks.wakeup.Broadcast()
+	return SingletonNUll
+}
+
+// wait until other function sends a value and return it. returns an error
+// instead of waiting forever when no function can send it
+func (c *Channel) Receive() Object {
+	c.tasks.lock.Lock()
+	defer c.tasks.lock.Unlock()
+
+	c.receivers++
+	c.tasks.wakeup.Broadcast()
+	received := c.tasks.wait(func() bool { return len(c.values) > 0 })
+	c.receivers--
+
+	if !received {
+		return deadlockError("recibir")
+	}
+
+	value := c.values[0]
+	c.values = c.values[1:]
+	c.tasks.wakeup.Broadcast()
+	return value
+}
+
+func (c *Channel) Type() ObjectType { return CHANNEL }
+func (c *Channel) Inspect() string  { return "canal" }
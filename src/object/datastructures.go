@@ -3,30 +3,26 @@ package object
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 type applyFunc func(Object, ...Object) Object
 type isTruthyFunc func(Object) bool
 
-// Represents an Array
+// Represents an Array, like all the objects the list is not safe for concurrent
+// use, the interpreter assumes that only one goroutine evaluates a script
 type List struct {
 	Values []Object // represents all the values in the array
+	Frozen bool     // represents if the list can not be modified anymore
 }
 
 func (l *List) Type() ObjectType { return LIST }
 func (l *List) Inspect() string {
-	var buf strings.Builder
-	for idx, val := range l.Values {
-		if idx == len(l.Values)-1 {
-			buf.WriteString(val.Inspect())
-		} else {
-			buf.WriteString(val.Inspect() + ", ")
-		}
-	}
-
-	return fmt.Sprintf("[%s]", buf.String())
+	return inspect(l, make(map[Object]bool))
 }
 
 // add a object to the values of the array
@@ -37,7 +33,7 @@ func (l *List) Add(obj Object) {
 // pop the last item in the array
 func (l *List) Pop() Object {
 	if len(l.Values) == 0 {
-		return &Error{"La lista esta vacia"}
+		return &Error{Message: "La lista esta vacia"}
 	}
 
 	obj := l.Values[len(l.Values)-1]
@@ -47,8 +43,8 @@ func (l *List) Pop() Object {
 
 // remove elements by index
 func (l *List) RemoveAt(index int) Object {
-	if index >= len(l.Values) || len(l.Values) == 0 {
-		return &Error{"Indice fuera de rango"}
+	if index < 0 || index >= len(l.Values) {
+		return &Error{Message: fmt.Sprintf("indice %d fuera de rango (longitud %d)", index, len(l.Values))}
 	}
 
 	val := l.Values[index]
@@ -56,14 +52,105 @@ func (l *List) RemoveAt(index int) Object {
 	return val
 }
 
+// return a new list with the values between start and end, the
+// indexes out of range are clamped
+func (l *List) Slice(start, end int) *List {
+	clamp := func(index int) int {
+		if index < 0 {
+			return 0
+		}
+
+		if index > len(l.Values) {
+			return len(l.Values)
+		}
+
+		return index
+	}
+
+	start, end = clamp(start), clamp(end)
+	values := []Object{}
+	if start < end {
+		values = append(values, l.Values[start:end]...)
+	}
+
+	return &List{Values: values}
+}
+
+// split the list in sublists of the given size, the last one may be shorter
+func (l *List) Chunk(size int) *List {
+	chunks := &List{Values: []Object{}}
+	for start := 0; start < len(l.Values); start += size {
+		chunks.Values = append(chunks.Values, l.Slice(start, start+size))
+	}
+
+	return chunks
+}
+
 func (l *List) Contains(obj Object) Object {
+	if l.Index(obj) == -1 {
+		return SingletonFALSE
+	}
+
+	return SingletonTRUE
+}
+
+// return the index of the first value equal to the object, -1 if there is none
+func (l *List) Index(obj Object) int {
+	for idx, val := range l.Values {
+		if Equal(val, obj) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// return a new list without the repeated values, the first of the equal
+// values is kept in its position
+func (l *List) Unique() *List {
+	unique := &List{Values: []Object{}}
 	for _, val := range l.Values {
-		if reflect.DeepEqual(val, obj) {
-			return SingletonTRUE
+		if unique.Index(val) == -1 {
+			unique.Values = append(unique.Values, val)
 		}
 	}
 
-	return SingletonFALSE
+	return unique
+}
+
+// return a new list with the values of the list and the values of the lists
+// nested inside it at any depth, a list that contains itself can not be flattened
+func (l *List) DeepFlatten() Object {
+	flat := &List{Values: []Object{}}
+	if !flatten(l, flat, make(map[*List]bool)) {
+		return &Error{Message: "no se puede aplanar una lista que se contiene a si misma"}
+	}
+
+	return flat
+}
+
+// add the values of the list to the flat list, return false if a list
+// contains itself
+func flatten(list *List, flat *List, visiting map[*List]bool) bool {
+	if visiting[list] {
+		return false
+	}
+
+	visiting[list] = true
+	defer delete(visiting, list)
+	for _, val := range list.Values {
+		nested, isList := val.(*List)
+		if !isList {
+			flat.Values = append(flat.Values, val)
+			continue
+		}
+
+		if !flatten(nested, flat, visiting) {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (l *List) Map(fn *Def, applyFunction applyFunc) *List {
@@ -75,6 +162,57 @@ func (l *List) Map(fn *Def, applyFunction applyFunc) *List {
 	return newList
 }
 
+// apply the function to all the values using a pool of goroutines and return
+// the results in the same order, if any call fails the first error by position
+// is returned. Each call has its own enviroment, the variables shared with
+// other calls are guarded by a lock but the values inside them are not
+func (l *List) ParallelMap(fn *Def, workers int, applyFunction applyFunc) Object {
+	results := make([]Object, len(l.Values))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	// the scopes visible to the workers need a lock
+	fn.Env.Synchronize()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		fn.Env.tasks.start()
+		go func() {
+			defer wg.Done()
+			defer fn.Env.tasks.end()
+			for index := range indexes {
+				results[index] = safeApply(fn, l.Values[index], applyFunction)
+			}
+		}()
+	}
+
+	for index := range l.Values {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, result := range results {
+		if err, isErr := result.(*Error); isErr && !err.Handled {
+			return err
+		}
+	}
+
+	return &List{Values: results}
+}
+
+// apply the function recovering from a panic so a failure in a goroutine
+// does not crash the host
+func safeApply(fn *Def, val Object, applyFunction applyFunc) (result Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = &Error{Message: fmt.Sprintf("error interno del interprete: %v", r)}
+		}
+	}()
+
+	return applyFunction(fn, val)
+}
+
 func (l *List) ForEach(fn *Def, applyFunction applyFunc) Object {
 	for _, val := range l.Values {
 		applyFunction(fn, val)
@@ -109,45 +247,334 @@ func (l *List) Count(fn *Def, applyFunction applyFunc, isTruthy isTruthyFunc) *N
 
 // represents a HashMap
 type Map struct {
-	Store map[string]Object // represents the hashmap it self
+	Store  map[string]Object // represents the hashmap it self
+	Keys   []Object          // represents the keys of the map in insertion order
+	Frozen bool              // represents if the map can not be modified anymore
 }
 
 func (m *Map) Type() ObjectType { return DICT }
+
+// the keys are always rendered in insertion order, so the same map built
+// in the same way has the same representation in every run
 func (m *Map) Inspect() string {
-	var buff = make([]string, 0, len(m.Store))
-	for key, val := range m.Store {
-		str := fmt.Sprintf("%s => %s", key, val.Inspect())
-		buff = append(buff, str)
+	return inspect(m, make(map[Object]bool))
+}
+
+// return the representation of the object. visiting has the collections that
+// are being inspected, so a collection that contains itself is shown like [...]
+// or {...} instead of being inspected forever
+func inspect(object Object, visiting map[Object]bool) string {
+	switch object := object.(type) {
+	case *List:
+		if visiting[object] {
+			return "[...]"
+		}
+
+		visiting[object] = true
+		defer delete(visiting, object)
+
+		values := make([]string, 0, len(object.Values))
+		for _, value := range object.Values {
+			values = append(values, inspect(value, visiting))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(values, ", "))
+
+	case *Map:
+		if visiting[object] {
+			return "{...}"
+		}
+
+		visiting[object] = true
+		defer delete(visiting, object)
+
+		values := make([]string, 0, len(object.Keys))
+		for _, key := range object.Keys {
+			value, _ := object.Get(key)
+			values = append(values, fmt.Sprintf("%s => %s", inspect(key, visiting), inspect(value, visiting)))
+		}
+		return fmt.Sprintf("{%s}", strings.Join(values, ", "))
+
+	case *Set:
+		if visiting[object] {
+			return "conjunto{...}"
+		}
+
+		visiting[object] = true
+		defer delete(visiting, object)
+
+		values := make([]string, 0, len(object.Values))
+		for _, value := range object.Values {
+			values = append(values, inspect(value, visiting))
+		}
+		return fmt.Sprintf("conjunto{%s}", strings.Join(values, ", "))
+
+	default:
+		return object.Inspect()
 	}
+}
+
+// return the key used in the store for the given object. the key is prefixed
+// with the type so keys like 1 and "1" does not collide, even inside lists or maps.
+// the numbers are compared by value, so a float without decimals like 1.0 is the
+// same key as the integer 1, like 1 == 1.0
+func (m *Map) Serialize(key Object) string {
+	return serialize(key)
+}
 
-	return fmt.Sprintf("{%s}", strings.Join(buff, ", "))
+// serialize the object prefixed with its type, used to compare the keys of
+// the maps and the values of the sets
+func serialize(key Object) string {
+	return serializeObject(key, make(map[Object]bool))
 }
 
-// get the value associeted with the given key if exists
-func (m *Map) Get(key string) Object {
-	obj, exists := m.Store[key]
-	if !exists {
-		return NullVAlue
+// serialize the object, a collection that contains itself is serialized
+// like ... in the place where it appears again
+func serializeObject(key Object, visiting map[Object]bool) string {
+	var values []string
+	switch key := key.(type) {
+	case *List, *Map, *Set:
+		if visiting[key] {
+			return fmt.Sprintf("%s:...", Types[key.Type()])
+		}
+
+		visiting[key] = true
+		defer delete(visiting, key)
 	}
 
-	return obj
+	switch key := key.(type) {
+	case *List:
+		for _, val := range key.Values {
+			values = append(values, serializeObject(val, visiting))
+		}
+
+	case *Map:
+		for _, mapKey := range key.Keys {
+			value, _ := key.Get(mapKey)
+			values = append(values, fmt.Sprintf("%s => %s", serializeObject(mapKey, visiting), serializeObject(value, visiting)))
+		}
+
+	case *Set:
+		for _, val := range key.Values {
+			values = append(values, serializeObject(val, visiting))
+		}
+
+	case *Char:
+		// a character is the same key as the text with the character, like 'a' == "a"
+		return serializeObject(&String{Value: string(key.Value)}, visiting)
+
+	case *Float:
+		// only the floats that an integer can represent are the same key as the
+		// integer, the bigger ones would overflow and share the same key
+		if key.Value == math.Trunc(key.Value) && key.Value >= math.MinInt64 && key.Value < math.MaxInt64 {
+			return serializeObject(&Number{Value: int(key.Value)}, visiting)
+		}
+		return fmt.Sprintf("%s:%s", Types[key.Type()], key.Inspect())
+
+	default:
+		return fmt.Sprintf("%s:%s", Types[key.Type()], key.Inspect())
+	}
+
+	return fmt.Sprintf("%s:[%s]", Types[key.Type()], strings.Join(values, ", "))
+}
+
+// get the value associeted with the given key and if the key exists, so a
+// key with a null value is different from a missing key
+func (m *Map) Get(key Object) (Object, bool) {
+	obj, exists := m.Store[m.Serialize(key)]
+	return obj, exists
 }
 
 // update the value associeted with the given key if exists
 // if not exists is just added to the map
 func (m *Map) UpdateKey(key, newVal Object) {
-	m.Store[key.Inspect()] = newVal
+	serialized := m.Serialize(key)
+	if _, exists := m.Store[serialized]; !exists {
+		m.Keys = append(m.Keys, key)
+	}
+	m.Store[serialized] = newVal
 }
 
 // Set the key value pair in the map and ckeck if the key already exists
 func (m *Map) SetValues(key Object, value Object) error {
-	if _, exists := m.Store[key.Inspect()]; exists {
+	serialized := m.Serialize(key)
+	if _, exists := m.Store[serialized]; exists {
 		return errors.New("la llave ya existe en el mapa")
 	}
-	m.Store[key.Inspect()] = value
+	m.Store[serialized] = value
+	m.Keys = append(m.Keys, key)
 	return nil
 }
 
+// return a frozen copy of the lists and maps, including the ones nested inside
+// them, so the original values can still be modified. the frozen values and
+// the other objects are shared because they can not be modified anymore
+func Freeze(object Object) Object {
+	return freezeObject(object, make(map[Object]Object))
+}
+
+// copy and freeze the object, the copies already made are reused so a list
+// that contains itself is frozen only once
+func freezeObject(object Object, copies map[Object]Object) Object {
+	if IsFrozen(object) {
+		return object
+	}
+
+	if frozen, exists := copies[object]; exists {
+		return frozen
+	}
+
+	switch object := object.(type) {
+	case *List:
+		frozen := &List{Values: make([]Object, len(object.Values)), Frozen: true}
+		copies[object] = frozen
+		for i, value := range object.Values {
+			frozen.Values[i] = freezeObject(value, copies)
+		}
+		return frozen
+
+	case *Map:
+		frozen := &Map{Store: map[string]Object{}, Frozen: true}
+		copies[object] = frozen
+		for _, key := range object.Keys {
+			value, _ := object.Get(key)
+			frozen.UpdateKey(key, freezeObject(value, copies))
+		}
+		return frozen
+
+	default:
+		return object
+	}
+}
+
+// check if the object is a frozen list or map
+func IsFrozen(object Object) bool {
+	switch object := object.(type) {
+	case *List:
+		return object.Frozen
+	case *Map:
+		return object.Frozen
+	default:
+		return false
+	}
+}
+
+// check if two objects are equal. the lists, maps and sets are compared by their
+// values, including the ones nested inside them, the numbers by value like
+// 1 == 1.0, all the nulls are equal and the functions and classes are only
+// equal to themselves
+func Equal(left, right Object) bool {
+	return equalObjects(left, right, make(map[[2]Object]bool))
+}
+
+// compare the objects. comparing has the pairs of collections that are being
+// compared, so a collection that contains itself is not compared forever
+func equalObjects(left, right Object, comparing map[[2]Object]bool) bool {
+	switch left.(type) {
+	case *List, *Map, *Set:
+		pair := [2]Object{left, right}
+		if comparing[pair] {
+			return true
+		}
+
+		comparing[pair] = true
+		defer delete(comparing, pair)
+	}
+
+	switch left := left.(type) {
+	case *Null:
+		_, isNull := right.(*Null)
+		return isNull
+
+	case *Number:
+		if right, isInt := right.(*Number); isInt {
+			return left.Value == right.Value
+		}
+
+		rightValue, isNumeric := numericValue(right)
+		return isNumeric && float64(left.Value) == rightValue
+
+	case *Float:
+		rightValue, isNumeric := numericValue(right)
+		return isNumeric && left.Value == rightValue
+
+	case *String, *Char:
+		leftText, _ := textValue(left)
+		rightText, isText := textValue(right)
+		return isText && leftText == rightText
+
+	case *List:
+		right, isList := right.(*List)
+		if !isList || len(left.Values) != len(right.Values) {
+			return false
+		}
+
+		for idx, value := range left.Values {
+			if !equalObjects(value, right.Values[idx], comparing) {
+				return false
+			}
+		}
+		return true
+
+	case *Map:
+		right, isMap := right.(*Map)
+		if !isMap || len(left.Keys) != len(right.Keys) {
+			return false
+		}
+
+		for _, key := range left.Keys {
+			leftValue, _ := left.Get(key)
+			rightValue, exists := right.Get(key)
+			if !exists || !equalObjects(leftValue, rightValue, comparing) {
+				return false
+			}
+		}
+		return true
+
+	case *Set:
+		right, isSet := right.(*Set)
+		if !isSet || len(left.Values) != len(right.Values) {
+			return false
+		}
+
+		for _, value := range left.Values {
+			if !right.Contains(value) {
+				return false
+			}
+		}
+		return true
+
+	case *Def, *Builtin, *Class:
+		return left == right
+
+	default:
+		return reflect.DeepEqual(left, right)
+	}
+}
+
+// return the value of an integer or a float as a float
+func numericValue(object Object) (float64, bool) {
+	switch object := object.(type) {
+	case *Number:
+		return float64(object.Value), true
+	case *Float:
+		return object.Value, true
+	default:
+		return 0, false
+	}
+}
+
+// return the value of a text or a character as a string
+func textValue(object Object) (string, bool) {
+	switch object := object.(type) {
+	case *String:
+		return object.Value, true
+	case *Char:
+		return string(object.Value), true
+	default:
+		return "", false
+	}
+}
+
 // represents the strings object
 type String struct {
 	Value string // represents the value of the string
@@ -183,6 +610,51 @@ func (s *String) Contains(val string) Object {
 	return SingletonFALSE
 }
 
+// return a new string filled with the pad until reach the width in characters,
+// the string is returned unchanged if is already at least the width
+func (s *String) Pad(width int, pad string, left bool) *String {
+	missing := width - utf8.RuneCountInString(s.Value)
+	if missing <= 0 {
+		return &String{Value: s.Value}
+	}
+
+	padRunes := []rune(strings.Repeat(pad, missing/utf8.RuneCountInString(pad)+1))
+	fill := string(padRunes[:missing])
+	if left {
+		return &String{Value: fill + s.Value}
+	}
+
+	return &String{Value: s.Value + fill}
+}
+
+// return the characters between start and end, the negative indexes count
+// from the end of the string and the indexes out of range are clamped
+func (s *String) Substring(start, end int) *String {
+	runes := []rune(s.Value)
+	clamp := func(index int) int {
+		if index < 0 {
+			index += len(runes)
+		}
+
+		if index < 0 {
+			return 0
+		}
+
+		if index > len(runes) {
+			return len(runes)
+		}
+
+		return index
+	}
+
+	start, end = clamp(start), clamp(end)
+	if start >= end {
+		return &String{Value: ""}
+	}
+
+	return &String{Value: string(runes[start:end])}
+}
+
 func (s *String) Split(sep string) *List {
 	list := strings.Split(s.Value, sep)
 	splited := new(List)
@@ -0,0 +1,40 @@
+package object
+
+// represents the result of a function running at the same time, the result
+// is available after the function returns
+type Future struct {
+	resolved bool       // represents if the function already returned
+	result   Object     // represents the value or the error returned by the function
+	tasks    *scheduler // represents the functions of the interpreter that created the future
+}
+
+// return a new future without result for the interpreter of the enviroment
+func NewFuture(env *Enviroment) *Future {
+	return &Future{tasks: env.tasks}
+}
+
+// store the result of the function and wake up the waiting functions, must be
+// called only once
+func (f *Future) Resolve(result Object) {
+	f.tasks.lock.Lock()
+	f.result = result
+	f.resolved = true
+	f.tasks.wakeup.Broadcast()
+	f.tasks.lock.Unlock()
+}
+
+// block until the result is ready and return it. returns an error instead of
+// waiting forever when the function is waiting for the caller
+func (f *Future) Wait() Object {
+	f.tasks.lock.Lock()
+	defer f.tasks.lock.Unlock()
+
+	if !f.tasks.wait(func() bool { return f.resolved }) {
+		return deadlockError("esperar")
+	}
+
+	return f.result
+}
+
+func (f *Future) Type() ObjectType { return FUTURE }
+func (f *Future) Inspect() string  { return "futuro" }
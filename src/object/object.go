@@ -2,8 +2,13 @@ package object
 
 import (
 	"aura/src/ast"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
 // represents all the types in the programming lenguage
@@ -27,6 +32,11 @@ const (
 	CLASS
 	BREAK
 	CONTINUE
+	TIME
+	CHANNEL
+	FUTURE
+	SET
+	CHARTYPE
 )
 
 // represents the methods in the standar library
@@ -49,6 +59,30 @@ const (
 	ISUPPER
 	ISLOWER
 	SPLIT
+	YEAR
+	MONTH
+	DAY
+	ABS
+	SIGN
+	CLAMP
+	STARTSWITH
+	ENDSWITH
+	PADLEFT
+	PADRIGHT
+	SUBSTRING
+	TAKE
+	SKIP
+	SUBLIST
+	CHUNK
+	PARALLELMAP
+	WAIT
+	DISCARD
+	UNION
+	INTERSECTION
+	DIFFERENCE
+	INDEX
+	UNIQUE
+	DEEPFLATTEN
 )
 
 // string representation of the types
@@ -65,7 +99,15 @@ var Types = [...]string{
 	LIST:       "lista",
 	METHOD:     "metodo",
 	DICT:       "mapa",
+	FLOATING:   "flotante",
 	CLASS:      "clase",
+	BREAK:      "romper",
+	CONTINUE:   "continuar",
+	TIME:       "fecha",
+	CHANNEL:    "canal",
+	FUTURE:     "futuro",
+	SET:        "conjunto",
+	CHARTYPE:   "caracter",
 }
 
 // Object is an interface for abstract all the structs
@@ -90,6 +132,27 @@ func NewFloat(val float64) *Float {
 func (f *Float) Type() ObjectType { return FLOATING }
 func (f *Float) Inspect() string  { return fmt.Sprint(f.Value) }
 
+// represents a character like 'a', it is equal to the text with the same character
+type Char struct{ Value rune }
+
+func (c *Char) Type() ObjectType { return CHARTYPE }
+func (c *Char) Inspect() string  { return string(c.Value) }
+
+// return the text of a text or a character, the character is used like the
+// text with only that character
+func AsText(object Object) (*String, bool) {
+	switch object := object.(type) {
+	case *String:
+		return object, true
+
+	case *Char:
+		return &String{Value: string(object.Value)}, true
+
+	default:
+		return nil, false
+	}
+}
+
 // represent the bool object
 type Bool struct{ Value bool }
 
@@ -121,6 +184,9 @@ func (r *Return) Inspect() string  { return r.Value.Inspect() }
 // represents the error object
 type Error struct {
 	Message string // represents the error message
+	Handled bool   // represents an error used as a value, it does not stop the evaluation
+	Line    int    // represents the line of the statement that failed, zero if is unknown
+	Column  int    // represents the column of the statement that failed, zero if is unknown
 }
 
 func (e *Error) Type() ObjectType { return ERROR }
@@ -171,23 +237,98 @@ func NewBuiltin(fn BuiltinFunction) *Builtin { return &Builtin{Fn: fn} }
 func (b *Builtin) Type() ObjectType { return BUILTIN }
 func (b *Builtin) Inspect() string  { return "builtin function" }
 
-// Represents a escope in the programming lengauge
+// Represents a escope in the programming lengauge, the enviroment is not safe for
+// concurrent use unless it is created with NewSyncEnviroment
 type Enviroment struct {
-	Store map[string]Object // repesents the store of all variables
-	outer *Enviroment       // represents a posible outer scope
-}
-
-// return a new enviroment instance
+	Store     map[string]Object // repesents the store of all variables
+	constants map[string]bool   // represents the variables declared with constante, nil if there is none
+	outer     *Enviroment       // represents a posible outer scope
+	network   *NetworkAccess    // represents the network capability, nil when is disabled
+	args      []string          // represents the command line arguments given by the host
+	stdout    io.Writer         // represents the output of escribir, os.Stdout by default
+	stderr    io.Writer         // represents the output for the diagnostics, os.Stderr by default
+	trace     TraceHook         // represents the hook called before each statement, nil when the tracing is disabled
+	profile   *Profile          // represents the counters of the evaluated nodes, nil when the profiling is disabled
+	mu        *sync.RWMutex     // represents the lock of the store, nil if is not synchronized
+	depth     int               // represents the number of nested function calls that created the scope
+	tasks     *scheduler        // represents the functions of the interpreter running at the same time, shared by all the scopes
+}
+
+// signature of the function called before evaluate each statement, used by
+// the hosts to implement debuggers
+type TraceHook func(node ast.ASTNode, env *Enviroment)
+
+// represents the permission given by the host to use the network builtins
+type NetworkAccess struct {
+	Timeout time.Duration // represents the max duration of a request
+}
+
+// return a new enviroment instance, the enviroment is synchronized if the
+// outer enviroment is synchronized and inherits the trace hook and the
+// profile of the outer
 func NewEnviroment(outer *Enviroment) *Enviroment {
-	return &Enviroment{
+	env := &Enviroment{
 		Store: make(map[string]Object),
 		outer: outer,
 	}
+
+	if outer != nil {
+		env.trace = outer.trace
+		env.profile = outer.profile
+		env.depth = outer.depth
+		env.tasks = outer.tasks
+	} else {
+		env.tasks = newScheduler()
+	}
+
+	if outer != nil && outer.mu != nil {
+		env.mu = new(sync.RWMutex)
+	}
+
+	return env
+}
+
+// return a new enviroment that guards its variables with a lock so it can be
+// shared by several goroutines, the scopes created from it are also synchronized.
+// The values like lists or maps are still not synchronized
+func NewSyncEnviroment(outer *Enviroment) *Enviroment {
+	env := NewEnviroment(outer)
+	env.mu = new(sync.RWMutex)
+	return env
+}
+
+// guard the enviroment and all the outer scopes with a lock, used before share
+// the enviroment with other goroutine
+func (e *Enviroment) Synchronize() {
+	for env := e; env != nil; env = env.outer {
+		if env.mu == nil {
+			env.mu = new(sync.RWMutex)
+		}
+	}
+}
+
+// acquire the lock of the store if the enviroment is synchronized and return
+// the function to release it
+func (e *Enviroment) lock(write bool) func() {
+	if e.mu == nil {
+		return func() {}
+	}
+
+	if write {
+		e.mu.Lock()
+		return e.mu.Unlock
+	}
+
+	e.mu.RLock()
+	return e.mu.RUnlock
 }
 
 // return a optional object if exists in the scope
 func (e *Enviroment) GetItem(key string) (Object, bool) {
+	unlock := e.lock(false)
 	val, exists := e.Store[key]
+	unlock()
+
 	if !exists {
 		// we check if there is an outer env and call the same method to find the object
 		if e.outer != nil {
@@ -201,18 +342,233 @@ func (e *Enviroment) GetItem(key string) (Object, bool) {
 
 // store an object in the eviroment
 func (e *Enviroment) SetItem(key string, val Object) {
+	defer e.lock(true)()
+	e.Store[key] = val
+}
+
+// update an item in the scope where it was declared, return false if the
+// item does not exists in any scope
+func (e *Enviroment) Reassign(key string, val Object) bool {
+	unlock := e.lock(true)
+	_, exists := e.Store[key]
+	if exists {
+		e.Store[key] = val
+	}
+	unlock()
+
+	if exists {
+		return true
+	}
+
+	if e.outer != nil {
+		return e.outer.Reassign(key, val)
+	}
+
+	return false
+}
+
+// store an object in the enviroment that can not be reassigned
+func (e *Enviroment) SetConstant(key string, val Object) {
+	defer e.lock(true)()
 	e.Store[key] = val
+	if e.constants == nil {
+		e.constants = make(map[string]bool)
+	}
+	e.constants[key] = true
+}
+
+// check if the variable visible with the given name was declared as a
+// constant, a variable of an inner scope can hide a constant of the outer
+func (e *Enviroment) IsConstant(key string) bool {
+	unlock := e.lock(false)
+	_, exists := e.Store[key]
+	constant := e.constants[key]
+	unlock()
+
+	if !exists && e.outer != nil {
+		return e.outer.IsConstant(key)
+	}
+
+	return constant
 }
 
 // delete an item form the enviroment
 func (e *Enviroment) DelItem(key string) {
+	defer e.lock(true)()
 	delete(e.Store, key)
+	delete(e.constants, key)
 }
 
 func (e *Enviroment) SetOuter(env *Enviroment) {
 	e.outer = env
 }
 
+// store the command line arguments that the scripts can read with argumentos
+func (e *Enviroment) SetArgs(args []string) {
+	e.args = args
+}
+
+// return the command line arguments of the enviroment or the outer scopes
+func (e *Enviroment) Args() []string {
+	if e.args == nil && e.outer != nil {
+		return e.outer.Args()
+	}
+
+	return e.args
+}
+
+// register a function that starts to run at the same time as the others of
+// the interpreter, EndTask must be called when it finishes
+func (e *Enviroment) StartTask() {
+	e.tasks.start()
+}
+
+// unregister a function started with StartTask
+func (e *Enviroment) EndTask() {
+	e.tasks.end()
+}
+
+// use the functions running at the same time of other interpreter, used by
+// the enviroments of the imported files
+func (e *Enviroment) ShareTasks(other *Enviroment) {
+	e.tasks = other.tasks
+}
+
+// change the number of nested function calls that created the scope
+func (e *Enviroment) SetCallDepth(depth int) {
+	e.depth = depth
+}
+
+// return the number of nested function calls that created the scope
+func (e *Enviroment) CallDepth() int {
+	return e.depth
+}
+
+// change the output where the values are written by escribir
+func (e *Enviroment) SetStdout(writer io.Writer) {
+	e.stdout = writer
+}
+
+// return the output of the enviroment or the outer scopes, os.Stdout if none
+// was set
+func (e *Enviroment) Stdout() io.Writer {
+	if e.stdout != nil {
+		return e.stdout
+	}
+
+	if e.outer != nil {
+		return e.outer.Stdout()
+	}
+
+	return os.Stdout
+}
+
+// change the output where the diagnostics and errors are written
+func (e *Enviroment) SetStderr(writer io.Writer) {
+	e.stderr = writer
+}
+
+// return the diagnostics output of the enviroment or the outer scopes,
+// os.Stderr if none was set
+func (e *Enviroment) Stderr() io.Writer {
+	if e.stderr != nil {
+		return e.stderr
+	}
+
+	if e.outer != nil {
+		return e.outer.Stderr()
+	}
+
+	return os.Stderr
+}
+
+// enable the network builtins for the scripts evaluated in the enviroment,
+// the network access is disabled by default
+func (e *Enviroment) AllowNetwork(timeout time.Duration) {
+	e.network = &NetworkAccess{Timeout: timeout}
+}
+
+// enable the tracing calling the hook before each statement evaluated in the
+// enviroment or the scopes created after it, nil disables the tracing
+func (e *Enviroment) SetTrace(hook TraceHook) {
+	e.trace = hook
+}
+
+// return the trace hook of the enviroment, nil if the tracing is disabled
+func (e *Enviroment) Trace() TraceHook {
+	return e.trace
+}
+
+// enable the counters of the evaluated nodes for the enviroment and the
+// scopes created after it
+func (e *Enviroment) EnableProfile() {
+	e.profile = NewProfile()
+}
+
+// return the profile of the enviroment, nil if the profiling is disabled
+func (e *Enviroment) Profile() *Profile {
+	return e.profile
+}
+
+// represents the variables of an enviroment at some point, it can only be
+// restored in the enviroment that created it
+type Snapshot struct {
+	env       *Enviroment       // represents the enviroment that was captured
+	store     map[string]Object // represents a copy of the variables of the enviroment
+	constants map[string]bool   // represents a copy of the constants of the enviroment
+}
+
+// capture the variables of the enviroment to restore them later. the copy is
+// shallow, so a list or map changed after the snapshot is not reverted, and
+// the variables of the outer scopes are not captured
+func (e *Enviroment) Snapshot() *Snapshot {
+	defer e.lock(false)()
+	return &Snapshot{env: e, store: copyStore(e.Store), constants: copyConstants(e.constants)}
+}
+
+// revert the variables of the enviroment to the snapshot, the variables
+// declared after the snapshot are removed. a snapshot can be restored many times
+func (e *Enviroment) Restore(snapshot *Snapshot) error {
+	if snapshot == nil || snapshot.env != e {
+		return errors.New("la captura no pertenece a este entorno")
+	}
+
+	defer e.lock(true)()
+	e.Store = copyStore(snapshot.store)
+	e.constants = copyConstants(snapshot.constants)
+	return nil
+}
+
+// return a shallow copy of the variables
+func copyStore(store map[string]Object) map[string]Object {
+	copied := make(map[string]Object, len(store))
+	for name, value := range store {
+		copied[name] = value
+	}
+
+	return copied
+}
+
+// return a copy of the names of the constants
+func copyConstants(constants map[string]bool) map[string]bool {
+	copied := make(map[string]bool, len(constants))
+	for name := range constants {
+		copied[name] = true
+	}
+
+	return copied
+}
+
+// return the network capability of the enviroment or the outer scopes,
+// nil if the network access is disabled
+func (e *Enviroment) Network() *NetworkAccess {
+	if e.network == nil && e.outer != nil {
+		return e.outer.Network()
+	}
+
+	return e.network
+}
+
 // repesents an iterator object
 type Iterator struct {
 	Current Object      // represents the current object
@@ -0,0 +1,41 @@
+package object
+
+import (
+	"aura/src/ast"
+	"reflect"
+	"sync"
+)
+
+// represents the counters of the evaluated nodes grouped by the node type,
+// it is safe for concurrent use
+type Profile struct {
+	mu     sync.Mutex     // represents the lock of the counters
+	counts map[string]int // represents how many times each node type was evaluated
+}
+
+// return a new profile without counts
+func NewProfile() *Profile {
+	return &Profile{counts: make(map[string]int)}
+}
+
+// increment the counter of the node type like Integer or Call
+func (p *Profile) Count(node ast.ASTNode) {
+	name := reflect.TypeOf(node).Elem().Name()
+
+	p.mu.Lock()
+	p.counts[name]++
+	p.mu.Unlock()
+}
+
+// return a copy of the counters
+func (p *Profile) Counts() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	counts := make(map[string]int, len(p.counts))
+	for name, count := range p.counts {
+		counts[name] = count
+	}
+
+	return counts
+}
@@ -0,0 +1,87 @@
+package object
+
+// represents a set of unique values, the values are compared with the same
+// serialization used by the keys of the maps
+type Set struct {
+	Store  map[string]Object // represents the values by their serialized key
+	Values []Object          // represents the values in insertion order
+}
+
+// return a new set with the given values, the repeated values are ignored
+func NewSet(values ...Object) *Set {
+	set := &Set{Store: map[string]Object{}, Values: []Object{}}
+	for _, value := range values {
+		set.Add(value)
+	}
+
+	return set
+}
+
+func (s *Set) Type() ObjectType { return SET }
+func (s *Set) Inspect() string {
+	return inspect(s, make(map[Object]bool))
+}
+
+// add the value to the set if is not already in the set
+func (s *Set) Add(value Object) {
+	key := serialize(value)
+	if _, exists := s.Store[key]; exists {
+		return
+	}
+
+	s.Store[key] = value
+	s.Values = append(s.Values, value)
+}
+
+// remove the value from the set, return false if the value was not in the set
+func (s *Set) Remove(value Object) bool {
+	key := serialize(value)
+	if _, exists := s.Store[key]; !exists {
+		return false
+	}
+
+	delete(s.Store, key)
+	for idx, element := range s.Values {
+		if serialize(element) == key {
+			s.Values = append(s.Values[:idx], s.Values[idx+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// check if the value is in the set
+func (s *Set) Contains(value Object) bool {
+	_, exists := s.Store[serialize(value)]
+	return exists
+}
+
+// return a new set with the values of both sets
+func (s *Set) Union(other *Set) *Set {
+	return NewSet(append(append([]Object{}, s.Values...), other.Values...)...)
+}
+
+// return a new set with the values that are in both sets
+func (s *Set) Intersection(other *Set) *Set {
+	result := NewSet()
+	for _, value := range s.Values {
+		if other.Contains(value) {
+			result.Add(value)
+		}
+	}
+
+	return result
+}
+
+// return a new set with the values that are not in the other set
+func (s *Set) Difference(other *Set) *Set {
+	result := NewSet()
+	for _, value := range s.Values {
+		if !other.Contains(value) {
+			result.Add(value)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+)
+
+// represents the functions of an interpreter running at the same time and the
+// ones waiting for a channel or a future, used to find when every function
+// would wait forever. each root enviroment has its own scheduler
+type scheduler struct {
+	lock    sync.Mutex       // represents the lock of the channels, the futures and the scheduler
+	wakeup  *sync.Cond       // represents the signal sent when a channel or a future changes
+	running int              // represents the functions running, including the main one
+	waiting map[*waiter]bool // represents the functions waiting for a channel or a future
+}
+
+// represents a function waiting until ready returns true
+type waiter struct {
+	ready    func() bool // represents the condition the function is waiting for
+	deadlock bool        // represents that the function would wait forever
+}
+
+// return a new scheduler with only the main function running
+func newScheduler() *scheduler {
+	s := &scheduler{running: 1, waiting: make(map[*waiter]bool)}
+	s.wakeup = sync.NewCond(&s.lock)
+	return s
+}
+
+// register a function that starts to run at the same time as the others
+func (s *scheduler) start() {
+	s.lock.Lock()
+	s.running++
+	s.lock.Unlock()
+}
+
+// unregister a function that finished, the functions still waiting could be
+// waiting for it
+func (s *scheduler) end() {
+	s.lock.Lock()
+	s.running--
+	s.checkDeadlock()
+	s.lock.Unlock()
+}
+
+// wait until ready returns true, return false if the function would wait
+// forever. a function marked in a deadlock stops waiting even if other marked
+// function changed the channel or future before. must be called with the lock
+// held
+func (s *scheduler) wait(ready func() bool) bool {
+	current := &waiter{ready: ready}
+	s.waiting[current] = true
+	defer delete(s.waiting, current)
+
+	for !current.deadlock {
+		if ready() {
+			return true
+		}
+
+		s.checkDeadlock()
+		if !current.deadlock {
+			s.wakeup.Wait()
+		}
+	}
+
+	return false
+}
+
+// if every running function is waiting and none of them can continue, all of
+// them are marked to stop waiting. must be called with the lock held
+func (s *scheduler) checkDeadlock() {
+	if len(s.waiting) < s.running {
+		return
+	}
+
+	for current := range s.waiting {
+		if current.ready() {
+			return
+		}
+	}
+
+	for current := range s.waiting {
+		current.deadlock = true
+	}
+	s.wakeup.Broadcast()
+}
+
+// return the error of an operation that would wait forever
+func deadlockError(operation string) *Error {
+	return &Error{Message: fmt.Sprintf("%s esperaria para siempre, todas las funciones estan esperando un canal o un futuro", operation)}
+}
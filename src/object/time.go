@@ -0,0 +1,16 @@
+package object
+
+import "time"
+
+// represents a date and time object, always in UTC
+type Time struct {
+	Value time.Time // represents the date and time
+}
+
+// return a new time instance in UTC
+func NewTime(value time.Time) *Time {
+	return &Time{Value: value.UTC()}
+}
+
+func (t *Time) Type() ObjectType { return TIME }
+func (t *Time) Inspect() string  { return t.Value.Format("2006-01-02 15:04:05") }
@@ -3,6 +3,7 @@ package parser
 import (
 	"aura/src/ast"
 	l "aura/src/lexer"
+	"fmt"
 )
 
 // parse a method expression
@@ -14,10 +15,21 @@ func (p *Parser) parseMethod(left ast.Expression) ast.Expression {
 		return nil
 	}
 
-	method := p.parseExpression(LOWEST)
+	method := p.parseMember()
 	return ast.NewMethodExpression(token, left, method)
 }
 
+// parse the identifier after a dot or a colon and its call if there is one
+func (p *Parser) parseMember() ast.Expression {
+	member := p.parseIdentifier()
+	if p.peekToken.Token_type == l.LPAREN {
+		p.advanceTokens()
+		member = p.parseCall(member)
+	}
+
+	return member
+}
+
 // parse an infix expressoin
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	p.checkCurrentTokenIsNotNil()
@@ -34,7 +46,27 @@ func (p *Parser) parseCall(function ast.Expression) ast.Expression {
 	p.checkCurrentTokenIsNotNil()
 	token := p.currentToken
 	args := p.parseExpressions(l.RPAREN)
-	return ast.NewCall(token, function, args...)
+	call := ast.NewCall(token, function)
+	for _, arg := range args {
+		if reassignment, isReassignment := arg.(*ast.Reassignment); isReassignment {
+			if name, isIdent := reassignment.Identifier.(*ast.Identifier); isIdent {
+				// a named argument like dibujar(x = 1)
+				call.Named = append(call.Named, ast.NewNamedArgument(reassignment.Token, name, reassignment.NewVal))
+				continue
+			}
+		}
+
+		if len(call.Named) > 0 {
+			// syntax error. we dont allow this -> dibujar(x = 1, 2)
+			message := fmt.Sprintf("los argumentos con nombre deben ir despues de los posicionales, se obtuvo %s en %s", arg.Str(), tokenPosition(token))
+			p.errors = append(p.errors, message)
+			return nil
+		}
+
+		call.Arguments = append(call.Arguments, arg)
+	}
+
+	return call
 }
 
 // parse a call list expression
@@ -77,11 +109,17 @@ func (p *Parser) parseKeyValues() *ast.KeyValue {
 
 // parse a range expression
 func (p *Parser) parseRangeExpression() ast.Expression {
-	token := p.currentToken
 	if !p.expepectedToken(l.IDENT) {
 		// syntax error. we dont allow this -> por(en rango(10))
 		return nil
 	}
+
+	return p.parseRangeVariable()
+}
+
+// parse a range expression when the current token is the variable
+func (p *Parser) parseRangeVariable() ast.Expression {
+	token := p.currentToken
 	variable := p.parseIdentifier()
 	if !p.expepectedToken(l.IN) {
 		// syntax error. we dont allow this -> por(i rango(10))
@@ -97,9 +135,12 @@ func (p *Parser) parseRangeExpression() ast.Expression {
 func (p *Parser) parseClassFieldsCall(left ast.Expression) ast.Expression {
 	p.checkCurrentTokenIsNotNil()
 	token := p.currentToken
-	p.checkPeekTokenIsNotNil()
-	p.advanceTokens()
-	field := p.parseExpression(LOWEST)
+	if !p.expepectedToken(l.IDENT) {
+		// syntax error. we dont allow this -> obj.5;
+		return nil
+	}
+
+	field := p.parseMember()
 	return ast.NewClassFieldCall(token, left, field)
 }
 
@@ -108,6 +149,9 @@ func (p *Parser) parseClassFieldsCall(left ast.Expression) ast.Expression {
 func (p *Parser) parseAssigmentExp(left ast.Expression) ast.Expression {
 	ident, isIdent := left.(*ast.Identifier)
 	if !isIdent {
+		// syntax error. we dont allow this -> 5 := 2;
+		message := fmt.Sprintf("solo se puede declarar una variable con :=, se obtuvo %s", left.Str())
+		p.errors = append(p.errors, message)
 		return nil
 	}
 
@@ -144,7 +188,10 @@ func (p *Parser) parseTernaryIf(condition ast.Expression) ast.Expression {
 	p.checkCurrentTokenIsNotNil()
 	token := p.currentToken
 	p.advanceTokens()
+	inTernary := p.inTernary
+	p.inTernary = true
 	consequence := p.parseExpression(PREFIX)
+	p.inTernary = inTernary
 	if !p.expepectedToken(l.COLON) {
 		return nil
 	}
@@ -4,6 +4,7 @@ import (
 	"aura/src/ast"
 	l "aura/src/lexer"
 	"fmt"
+	"strings"
 )
 
 // Signature for functions to parse prefix expressions
@@ -22,6 +23,12 @@ type SuffixParseFns map[l.TokenType]SuffixParseFn
 // represents the precedence of evaluation
 type Precedence int
 
+// represents the default max nesting of expressions allowed in the parser
+const DefaultMaxDepth = 1000
+
+// represents a parse that was stopped because the expressions were too nested
+type bailout struct{}
+
 const (
 	HeadPrecendence Precedence = iota
 	LOWEST                     = 1
@@ -31,7 +38,9 @@ const (
 	SUM                        = 5
 	PRODUCT                    = 6
 	PREFIX                     = 7
-	CALL                       = 8
+	SUFFIX                     = 8
+	CALL                       = 9
+	MEMBER                     = 10
 )
 
 var precedences = map[l.TokenType]Precedence{
@@ -51,15 +60,15 @@ var precedences = map[l.TokenType]Precedence{
 	l.LBRACKET:    CALL,
 	l.OR:          ANDOR,
 	l.ASSING:      ANDOR,
-	l.COLON:       PREFIX,
+	l.COLON:       MEMBER,
 	l.PLUSASSING:  PRODUCT,
 	l.MINUSASSING: PRODUCT,
 	l.DIVASSING:   PRODUCT,
-	l.EXPONENT:    PRODUCT,
+	l.EXPONENT:    SUFFIX,
 	l.TIMEASSI:    PRODUCT,
-	l.PLUS2:       PRODUCT,
-	l.MINUS2:      PRODUCT,
-	l.DOT:         PREFIX,
+	l.PLUS2:       SUFFIX,
+	l.MINUS2:      SUFFIX,
+	l.DOT:         MEMBER,
 	l.COLONASSING: PREFIX,
 	l.QUESTION:    PRODUCT,
 }
@@ -74,6 +83,9 @@ type Parser struct {
 	prefixParsFns  PrefixParsFns  // represents all the functions to parse prefix expressions
 	infixParseFns  InfixParseFns  // represents all the functions to parse infix expressions
 	suffixParseFns SuffixParseFns // represents all the functions to parse suffix expressions
+	inTernary      bool           // represents if the parser is reading the consequence of a ternary if
+	depth          int            // represents the current nesting of expressions
+	maxDepth       int            // represents the max nesting of expressions allowed
 }
 
 // generates a new parser instance
@@ -85,6 +97,7 @@ func NewParser(lexer *l.Lexer) *Parser {
 		prefixParsFns:  make(PrefixParsFns),
 		infixParseFns:  make(InfixParseFns),
 		suffixParseFns: make(SuffixParseFns),
+		maxDepth:       DefaultMaxDepth,
 	}
 
 	// we register all the functions to parse the expressions
@@ -135,9 +148,22 @@ func (p *Parser) Errors() []string {
 	return p.errors
 }
 
+// change the max nesting of expressions allowed before stop the parsing
+func (p *Parser) SetMaxDepth(depth int) {
+	p.maxDepth = depth
+}
+
 // parse all the program
-func (p *Parser) ParseProgam() *ast.Program {
-	program := new(ast.Program)
+func (p *Parser) ParseProgam() (program *ast.Program) {
+	program = new(ast.Program)
+	defer func() {
+		// the parsing is stopped when the expressions are too nested
+		if r := recover(); r != nil {
+			if _, isBailout := r.(bailout); !isBailout {
+				panic(r)
+			}
+		}
+	}()
 
 	for p.currentToken.Token_type != l.EOF {
 		if statement := p.parseStament(); statement != nil {
@@ -164,14 +190,35 @@ func (p *Parser) expepectedToken(tokenType l.TokenType) bool {
 // add an error to errors list if there is any unexpected token error
 func (p *Parser) expectedTokenError(tokenType l.TokenType) {
 	p.checkCurrentTokenIsNotNil()
+	if p.peekToken.Token_type == l.EOF || p.currentToken.Token_type == l.EOF {
+		p.unexpectedEOFError(l.Tokens[tokenType])
+		return
+	}
+
 	err := fmt.Sprintf(
-		"se esperaba que el siguient token fuera %s pero se obtuvo %s",
+		"se esperaba que el siguient token fuera %s pero se obtuvo %s en %s",
 		l.Tokens[tokenType],
 		l.Tokens[p.peekToken.Token_type],
+		tokenPosition(p.peekToken),
 	)
 	p.errors = append(p.errors, err)
 }
 
+// return the position of the token for the error messages like linea 4, columna 7
+func tokenPosition(token *l.Token) string {
+	return fmt.Sprintf("linea %d, columna %d", token.Line, token.Column)
+}
+
+// add an error to errors list when the source ends before the expected token
+func (p *Parser) unexpectedEOFError(expected string) {
+	// only the first error is reported because the rest are caused by the same end
+	if len(p.errors) > 0 && strings.HasPrefix(p.errors[len(p.errors)-1], "fin de archivo inesperado") {
+		return
+	}
+
+	p.errors = append(p.errors, fmt.Sprintf("fin de archivo inesperado, se esperaba %s", expected))
+}
+
 // parseBlock will parse a block expression
 func (p *Parser) parseBlock() *ast.Block {
 	p.checkCurrentTokenIsNotNil()
@@ -188,6 +235,11 @@ func (p *Parser) parseBlock() *ast.Block {
 		p.advanceTokens()
 	}
 
+	if p.currentToken.Token_type == l.EOF {
+		// the block was never closed like si (x) { x
+		p.unexpectedEOFError("}")
+	}
+
 	return ast.NewBlock(token, stmts...)
 }
 
@@ -202,12 +254,24 @@ func (p *Parser) parseExpressions(delimiter l.TokenType) []ast.Expression {
 	}
 
 	p.advanceTokens()
-	if expression := p.parseExpression(LOWEST); expression != nil {
-		values = append(values, expression)
+	return p.parseRemainingExpressions(p.parseExpression(LOWEST), delimiter)
+}
+
+// parse the rest of a slice of expressions when the first expression
+// was already parsed
+func (p *Parser) parseRemainingExpressions(first ast.Expression, delimiter l.TokenType) []ast.Expression {
+	var values []ast.Expression
+	if first != nil {
+		values = append(values, first)
 	}
 
 	for p.peekToken.Token_type == l.COMMA {
 		p.advanceTokens()
+		if p.peekToken.Token_type == delimiter {
+			// trailing comma like f(1, 2,)
+			break
+		}
+
 		p.advanceTokens()
 		if expression := p.parseExpression(LOWEST); expression != nil {
 			values = append(values, expression)
@@ -221,15 +285,64 @@ func (p *Parser) parseExpressions(delimiter l.TokenType) []ast.Expression {
 	return values
 }
 
+// parse the iteration and the optional condition of a comprehension like:
+//		por (x en valores) si (x > 0)
+func (p *Parser) parseComprehension() (ast.Expression, ast.Expression) {
+	p.advanceTokens()
+	if !p.expepectedToken(l.LPAREN) {
+		// syntax error -> lista[x por x en valores]
+		return nil, nil
+	}
+
+	iteration := p.parseRangeExpression()
+	if iteration == nil || !p.expepectedToken(l.RPAREN) {
+		return nil, nil
+	}
+
+	var condition ast.Expression = nil
+	if p.peekToken.Token_type == l.IF {
+		p.advanceTokens()
+		if !p.expepectedToken(l.LPAREN) {
+			// syntax error -> lista[x por (x en valores) si x > 0]
+			return nil, nil
+		}
+
+		p.advanceTokens()
+		condition = p.parseExpression(LOWEST)
+		if !p.expepectedToken(l.RPAREN) {
+			return nil, nil
+		}
+	}
+
+	return iteration, condition
+}
+
 // parse a expression based on the given precedence
 func (p *Parser) parseExpression(precedence Precedence) ast.Expression {
 	p.checkCurrentTokenIsNotNil()
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > p.maxDepth {
+		message := fmt.Sprintf("la expresion excede la profundidad maxima de %d niveles", p.maxDepth)
+		p.errors = append(p.errors, message)
+		panic(bailout{})
+	}
+
+	if p.currentToken.Token_type == l.EOF {
+		// the source ends in the middle of an expression like x := 5 +
+		p.unexpectedEOFError("una expresion")
+		return nil
+	}
 
 	// we check if there is any function to parse the current token
 	prefixParseFn, exist := p.prefixParsFns[p.currentToken.Token_type]
 	if !exist {
 		// there is no function to parse the token
-		message := fmt.Sprintf("no se encontro ninguna funcion para parsear %s", p.currentToken.Literal)
+		message := fmt.Sprintf(
+			"no se encontro ninguna funcion para parsear %s en %s",
+			p.currentToken.Literal,
+			tokenPosition(p.currentToken),
+		)
 		p.errors = append(p.errors, message)
 		return nil
 	}
@@ -237,26 +350,29 @@ func (p *Parser) parseExpression(precedence Precedence) ast.Expression {
 	leftExpression := prefixParseFn()
 	p.checkPeekTokenIsNotNil()
 
-	// we check if there is any suffix expression to be parsed
-	if suffixFn, exists := p.suffixParseFns[p.peekToken.Token_type]; exists {
-		p.advanceTokens()
-		leftExpression = suffixFn(leftExpression)
-		p.advanceTokens()
-	}
-
 	// we loop until the precedence is lowest than the next precedence
 	for p.peekToken.Token_type != l.SEMICOLON && precedence < p.peekPrecedence() {
+		// the suffix expressions apply to any expression before them like a[0]++
+		if suffixFn, exists := p.suffixParseFns[p.peekToken.Token_type]; exists {
+			p.advanceTokens()
+			leftExpression = suffixFn(leftExpression)
+			continue
+		}
+
 		// we check if there is any function to parse an infix expression
 		infixParseFn, exist := p.infixParseFns[p.peekToken.Token_type]
 		if !exist {
 			return leftExpression
 		}
 
-		p.advanceTokens()
 		if leftExpression == nil {
-			panic("Error de parseo :(")
+			// the prefix expression failed and its error was already added,
+			// like a group without value in () + 1
+			return nil
 		}
 
+		p.advanceTokens()
+
 		leftExpression = infixParseFn(leftExpression)
 	}
 
@@ -283,17 +399,45 @@ func (p *Parser) parseClassStatement() ast.Stmt {
 
 	p.advanceTokens()
 	methods := make([]*ast.ClassMethodExp, 0)
-	for p.currentToken.Token_type != l.RBRACE && p.currentToken.Token_type != l.EOF {
-		if expression := p.parseClassMethod(); expression != nil {
-			if method, isMethod := expression.(*ast.ClassMethodExp); isMethod {
-				methods = append(methods, method)
-			}
+	for p.currentToken.Token_type != l.RBRACE {
+		if p.currentToken.Token_type == l.EOF {
+			p.unexpectedEOFError(l.Tokens[l.RBRACE])
+			return nil
+		}
+
+		method, isMethod := p.parseClassMethod().(*ast.ClassMethodExp)
+		if !isMethod {
+			// the method is malformed, the rest of the class is skipped so the
+			// parser always makes progress
+			p.skipClassBody()
+			return nil
 		}
+
+		methods = append(methods, method)
 	}
 
 	return ast.NewClassStatement(token, name, params, methods)
 }
 
+// advance the tokens until the brace that closes the current class body
+func (p *Parser) skipClassBody() {
+	depth := 1
+	for p.currentToken.Token_type != l.EOF {
+		switch p.currentToken.Token_type {
+		case l.LBRACE:
+			depth++
+
+		case l.RBRACE:
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+
+		p.advanceTokens()
+	}
+}
+
 // parse a expression statement
 func (p *Parser) parserExpressionStatement() *ast.ExpressionStament {
 	p.checkCurrentTokenIsNotNil()
@@ -343,6 +487,17 @@ func (p *Parser) parseLetSatement() ast.Stmt {
 	return ast.NewLetStatement(token, name, value)
 }
 
+// parse a constant declaration like constante x = 5; it has the same syntax
+// of a let statement
+func (p *Parser) parseConstStatement() ast.Stmt {
+	let, isLet := p.parseLetSatement().(*ast.LetStatement)
+	if !isLet {
+		return nil
+	}
+
+	return ast.NewConstStatement(let.Token, let.Name, let.Value)
+}
+
 // parse an array of identifiers. this function will be use be use to parse params
 // of a function or class constructors
 func (p *Parser) parseIdentifiers(delimiter l.TokenType) []*ast.Identifier {
@@ -352,17 +507,23 @@ func (p *Parser) parseIdentifiers(delimiter l.TokenType) []*ast.Identifier {
 		return values
 	}
 
-	p.advanceTokens()
-	if ident := p.parseIdentifier().(*ast.Identifier); ident != nil {
-		values = append(values, ident)
+	if !p.expepectedToken(l.IDENT) {
+		// syntax error. a comma without a parameter -> funcion(,)
+		return make([]*ast.Identifier, 0)
 	}
+	values = append(values, p.parseIdentifier().(*ast.Identifier))
 
 	for p.peekToken.Token_type == l.COMMA {
 		p.advanceTokens()
-		p.advanceTokens()
-		if ident := p.parseIdentifier().(*ast.Identifier); ident != nil {
-			values = append(values, ident)
+		if p.peekToken.Token_type == delimiter {
+			// trailing comma like funcion(a, b,)
+			break
+		}
+
+		if !p.expepectedToken(l.IDENT) {
+			return make([]*ast.Identifier, 0)
 		}
+		values = append(values, p.parseIdentifier().(*ast.Identifier))
 	}
 
 	if !p.expepectedToken(delimiter) {
@@ -412,6 +573,9 @@ func (p *Parser) parseStament() ast.Stmt {
 	case l.LET:
 		return p.parseLetSatement()
 
+	case l.CONST:
+		return p.parseConstStatement()
+
 	case l.RETURN:
 		return p.parseReturnStatement()
 
@@ -435,6 +599,11 @@ func (p *Parser) parseStament() ast.Stmt {
 // return the precedence of the next token
 func (p *Parser) peekPrecedence() Precedence {
 	p.checkPeekTokenIsNotNil()
+	if p.inTernary && p.peekToken.Token_type == l.COLON {
+		// the colon closes the consequence of the ternary if
+		return LOWEST
+	}
+
 	precedence, exists := precedences[p.peekToken.Token_type]
 	if !exists {
 		return LOWEST
@@ -487,6 +656,8 @@ func (p *Parser) registerPrefixFns() {
 	p.prefixParsFns[l.NOT] = p.parsePrefixExpression
 	p.prefixParsFns[l.TRUE] = p.parseBoolean
 	p.prefixParsFns[l.STRING] = p.parseStringLiteral
+	p.prefixParsFns[l.CHAR] = p.parseChar
+	p.prefixParsFns[l.TEMPLATE] = p.parseInterpolatedString
 	p.prefixParsFns[l.DATASTRCUT] = p.ParseArray
 	p.prefixParsFns[l.NULLT] = p.ParseNull
 	p.prefixParsFns[l.MAP] = p.parseMap
@@ -495,6 +666,8 @@ func (p *Parser) registerPrefixFns() {
 	p.prefixParsFns[l.BAR] = p.parseArrowFunc
 	p.prefixParsFns[l.TRY] = p.parseTryExp
 	p.prefixParsFns[l.THROW] = p.ParseTrhowExp
+	p.prefixParsFns[l.MATCH] = p.parseMatch
+	p.prefixParsFns[l.ILLEGAL] = p.parseIllegal
 }
 
 // register all the functions to parse suffix expressions
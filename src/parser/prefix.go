@@ -5,6 +5,8 @@ import (
 	l "aura/src/lexer"
 	"fmt"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 // parse a boolean expression
@@ -28,7 +30,14 @@ func (p *Parser) parseFor() ast.Expression {
 		// syntax error -> por i en rango(10))
 		return nil
 	}
-	condition := p.parseRangeExpression()
+
+	p.advanceTokens()
+	if p.currentToken.Token_type != l.IDENT || p.peekToken.Token_type != l.IN {
+		// we have a classic for loop like por (var i = 0; i < 10; i++)
+		return p.parseClassicFor(token)
+	}
+
+	condition := p.parseRangeVariable()
 	if !p.expepectedToken(l.RPAREN) {
 		// syntax error -> por(i en range(10)
 		return nil
@@ -44,6 +53,48 @@ func (p *Parser) parseFor() ast.Expression {
 	return ast.NewFor(token, condition, body)
 }
 
+// parse a classic for expression with an init statement, a condition and
+// a post expression, all of them are optional:
+//		por (var i = 0; i < 10; i++) { ... }
+func (p *Parser) parseClassicFor(token *l.Token) ast.Expression {
+	var init ast.Stmt = nil
+	if p.currentToken.Token_type != l.SEMICOLON {
+		init = p.parseStament()
+		// the statements consume the semicolon if there is one
+		if p.currentToken.Token_type != l.SEMICOLON && !p.expepectedToken(l.SEMICOLON) {
+			return nil
+		}
+	}
+
+	var condition ast.Expression = nil
+	if p.peekToken.Token_type != l.SEMICOLON {
+		p.advanceTokens()
+		condition = p.parseExpression(LOWEST)
+	}
+
+	if !p.expepectedToken(l.SEMICOLON) {
+		// syntax error -> por (var i = 0; i < 10) {}
+		return nil
+	}
+
+	var post ast.Expression = nil
+	if p.peekToken.Token_type != l.RPAREN {
+		p.advanceTokens()
+		post = p.parseExpression(LOWEST)
+	}
+
+	if !p.expepectedToken(l.RPAREN) {
+		return nil
+	}
+
+	if !p.expepectedToken(l.LBRACE) {
+		return nil
+	}
+
+	body := p.parseBlock()
+	return ast.NewClassicFor(token, init, condition, post, body)
+}
+
 // parse a function expression
 func (p *Parser) parseFunction() ast.Expression {
 	p.checkCurrentTokenIsNotNil()
@@ -133,15 +184,24 @@ func (p *Parser) parseIf() ast.Expression {
 	}
 
 	consequence := p.parseBlock()
-	var alternative *ast.Block = nil
+	var alternative ast.ASTNode = nil
 	p.checkPeekTokenIsNotNil()
 	// if we have an else token that means there is an else expression
 	if p.peekToken.Token_type == l.ELSE {
 		p.advanceTokens()
-		if !p.expepectedToken(l.LBRACE) {
-			return nil
+		if p.peekToken.Token_type == l.IF {
+			// an else if like si (a) { } si_no si (b) { }, the next if is the alternative
+			p.advanceTokens()
+			alternative = p.parseIf()
+			if alternative == nil {
+				return nil
+			}
+		} else {
+			if !p.expepectedToken(l.LBRACE) {
+				return nil
+			}
+			alternative = p.parseBlock()
 		}
-		alternative = p.parseBlock()
 	}
 
 	return ast.NewIf(token, condition, consequence, alternative)
@@ -151,8 +211,12 @@ func (p *Parser) parseIf() ast.Expression {
 func (p *Parser) parseInteger() ast.Expression {
 	p.checkCurrentTokenIsNotNil()
 	token := p.currentToken
+	literal, valid := p.removeDigitSeparators(token.Literal)
+	if !valid {
+		return nil
+	}
 
-	val, err := strconv.Atoi(p.currentToken.Literal)
+	val, err := strconv.Atoi(literal)
 	if err != nil {
 		// the value is not a number. this is very weird to happend
 		message := fmt.Sprintf("no se pudo parsear %s como entero", p.currentToken.Literal)
@@ -167,7 +231,16 @@ func (p *Parser) parseInteger() ast.Expression {
 func (p *Parser) parseFloat() ast.Expression {
 	p.checkCurrentTokenIsNotNil()
 	token := p.currentToken
-	val, err := strconv.ParseFloat(p.currentToken.Literal, 64)
+	if !p.checkExponent(token.Literal) {
+		return nil
+	}
+
+	literal, valid := p.removeDigitSeparators(token.Literal)
+	if !valid {
+		return nil
+	}
+
+	val, err := strconv.ParseFloat(literal, 64)
 	if err != nil {
 		// the value is not a float. this is very weird to happend
 		message := fmt.Sprintf("no se pudo parsear %s como flotante", p.currentToken.Literal)
@@ -178,10 +251,89 @@ func (p *Parser) parseFloat() ast.Expression {
 	return ast.NewFloatExp(token, val)
 }
 
+// check that the exponent of a number in scientific notation like 1.5e3
+// has digits after the optional sign
+func (p *Parser) checkExponent(literal string) bool {
+	start := strings.IndexAny(literal, "eE")
+	if start == -1 {
+		return true
+	}
+
+	digits := strings.TrimLeft(literal[start+1:], "+-")
+	if digits != "" {
+		return true
+	}
+
+	message := fmt.Sprintf(
+		"exponente no valido en %s, se esperaban digitos despues de %s en %s",
+		literal,
+		literal[start:],
+		tokenPosition(p.currentToken),
+	)
+	p.errors = append(p.errors, message)
+	return false
+}
+
+// return the number literal without the digit separators like 1_000, the
+// separators are only valid between two digits
+func (p *Parser) removeDigitSeparators(literal string) (string, bool) {
+	isSeparator := func(char rune) bool {
+		return strings.ContainsRune(".eE+-", char)
+	}
+
+	for _, digits := range strings.FieldsFunc(literal, isSeparator) {
+		if strings.HasPrefix(digits, "_") || strings.HasSuffix(digits, "_") || strings.Contains(digits, "__") {
+			message := fmt.Sprintf(
+				"separador de digitos no valido en %s en %s",
+				literal,
+				tokenPosition(p.currentToken),
+			)
+			p.errors = append(p.errors, message)
+			return "", false
+		}
+	}
+
+	return strings.ReplaceAll(literal, "_", ""), true
+}
+
+// advance until the parenthesis that closes the current group, so the rest
+// of the group does not add more errors
+func (p *Parser) skipGroup() {
+	depth := 1
+	for depth > 0 && p.peekToken.Token_type != l.EOF {
+		p.advanceTokens()
+		switch p.currentToken.Token_type {
+		case l.LPAREN:
+			depth++
+		case l.RPAREN:
+			depth--
+		}
+	}
+}
+
 // parse a group expression like (5 + 5) / 2
 func (p *Parser) parseGroupExpression() ast.Expression {
+	if p.peekToken.Token_type == l.RPAREN {
+		// an empty group like () is not a value
+		message := fmt.Sprintf("expresion vacia entre parentesis en %s", tokenPosition(p.currentToken))
+		p.errors = append(p.errors, message)
+		p.advanceTokens()
+		return nil
+	}
+
 	p.advanceTokens()
 	expression := p.parseExpression(LOWEST)
+	if p.peekToken.Token_type == l.COMMA {
+		// there are no tuples, so a group like (a, b) is an error
+		message := fmt.Sprintf(
+			"las tuplas no estan soportadas, se esperaba ) pero se obtuvo , en %s",
+			tokenPosition(p.peekToken),
+		)
+		p.errors = append(p.errors, message)
+		p.skipGroup()
+		return nil
+	}
+
 	if !p.expepectedToken(l.RPAREN) {
 		// syntax error: missing parenthessis
 		return nil
@@ -207,23 +359,190 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return ast.NewStringLiteral(p.currentToken, p.currentToken.Literal)
 }
 
+// parse a character literal like 'a', the lexer checks that the literal has
+// only one character
+func (p *Parser) parseChar() ast.Expression {
+	p.checkCurrentTokenIsNotNil()
+	char, _ := utf8.DecodeRuneInString(p.currentToken.Literal)
+	return ast.NewChar(p.currentToken, char)
+}
+
+// add the error of an illegal token. the lexer keeps the illegal character in
+// the literal or the reason when a literal is not closed like texto sin cerrar
+func (p *Parser) parseIllegal() ast.Expression {
+	p.checkCurrentTokenIsNotNil()
+	reason := p.currentToken.Literal
+	if utf8.RuneCountInString(reason) == 1 {
+		reason = fmt.Sprintf("caracter no valido %s", reason)
+	}
+
+	p.errors = append(p.errors, fmt.Sprintf("%s en %s", reason, tokenPosition(p.currentToken)))
+	return nil
+}
+
+// parse a string with interpolated expressions like:
+//		"hola ${nombre}, tienes ${edad + 1}"
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	p.checkCurrentTokenIsNotNil()
+	token := p.currentToken
+	source := token.Literal
+	var parts []ast.Expression
+	var text strings.Builder
+
+	for i := 0; i < len(source); i++ {
+		if strings.HasPrefix(source[i:], `\${`) {
+			// escaped interpolation, we keep the literal
+			text.WriteString("${")
+			i += 2
+			continue
+		}
+
+		if source[i] == '\\' && i+1 < len(source) {
+			if char, isEscape := l.EscapeSequence(source[i+1]); isEscape {
+				text.WriteString(char)
+				i++
+				continue
+			}
+		}
+
+		if !strings.HasPrefix(source[i:], "${") {
+			text.WriteByte(source[i])
+			continue
+		}
+
+		if text.Len() > 0 {
+			parts = append(parts, ast.NewStringLiteral(token, text.String()))
+			text.Reset()
+		}
+
+		end := interpolationEnd(source, i+2)
+		if end == -1 {
+			p.errors = append(p.errors, fmt.Sprintf("interpolacion sin cerrar en %s", source))
+			return nil
+		}
+
+		expression := p.parseInterpolation(source[i+2 : end])
+		if expression == nil {
+			return nil
+		}
+
+		parts = append(parts, expression)
+		i = end
+	}
+
+	if text.Len() > 0 {
+		parts = append(parts, ast.NewStringLiteral(token, text.String()))
+	}
+
+	return ast.NewInterpolatedString(token, parts...)
+}
+
+// parse the source of an interpolated expression with a new parser
+func (p *Parser) parseInterpolation(source string) ast.Expression {
+	if strings.TrimSpace(source) == "" {
+		p.errors = append(p.errors, "se esperaba una expresion dentro de ${}")
+		return nil
+	}
+
+	// the interpolation continues the nesting of the string that contains it
+	parser := NewParser(l.NewLexer(source))
+	parser.depth, parser.maxDepth = p.depth, p.maxDepth
+	defer func() { p.errors = append(p.errors, parser.Errors()...) }()
+
+	expression := parser.parseExpression(LOWEST)
+	if parser.peekToken.Token_type != l.EOF && parser.peekToken.Token_type != l.SEMICOLON {
+		message := fmt.Sprintf("se esperaba } pero se obtuvo %s en ${%s}", parser.peekToken.Literal, source)
+		parser.errors = append(parser.errors, message)
+		return nil
+	}
+
+	return expression
+}
+
+// return the index of the brace that closes the interpolation that starts in
+// the given index, ignoring the braces inside strings. -1 if there is none
+func interpolationEnd(source string, start int) int {
+	depth := 1
+	var quote byte = 0
+	for i := start; i < len(source); i++ {
+		switch character := source[i]; {
+		case quote != 0 && character == '\\':
+			// the escaped character can not close the string
+			i++
+
+		case quote != 0:
+			if character == quote {
+				quote = 0
+			}
+
+		case character == '"' || character == '\'':
+			quote = character
+
+		case character == '{':
+			depth++
+
+		case character == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
 // parse a array expression
 func (p *Parser) ParseArray() ast.Expression {
 	p.checkCurrentTokenIsNotNil()
 	token := p.currentToken
+	if p.peekToken.Token_type == l.LPAREN {
+		// we have a call to the lista builtin like lista(rango(5))
+		return p.parseConstructorCall()
+	}
+
 	if !p.expepectedToken(l.LBRACKET) {
 		// syntax error -> lista 2,3,4,5
 		return nil
 	}
-	values := p.parseExpressions(l.RBRACKET)
+	if p.peekToken.Token_type == l.RBRACKET {
+		p.advanceTokens()
+		return ast.NewArray(token)
+	}
+
+	p.advanceTokens()
+	first := p.parseExpression(LOWEST)
+	if p.peekToken.Token_type == l.FOR {
+		// we have a list comprehension like lista[x * 2 por (x en valores)]
+		iteration, condition := p.parseComprehension()
+		if iteration == nil || !p.expepectedToken(l.RBRACKET) {
+			return nil
+		}
+
+		return ast.NewListComprehension(token, first, iteration, condition)
+	}
+
+	values := p.parseRemainingExpressions(first, l.RBRACKET)
 	return ast.NewArray(token, values...)
 }
 
+// parse a call to the builtin with the same name of a data structure keyword
+func (p *Parser) parseConstructorCall() ast.Expression {
+	function := ast.NewIdentifier(p.currentToken, p.currentToken.Literal)
+	p.advanceTokens()
+	return p.parseCall(function)
+}
+
 // parse a map expression
 func (p *Parser) parseMap() ast.Expression {
 	p.checkCurrentTokenIsNotNil()
 	token := p.currentToken
 	keyValues := make([]*ast.KeyValue, 0)
+	if p.peekToken.Token_type == l.LPAREN {
+		// we have a call to the mapa builtin like mapa(pares)
+		return p.parseConstructorCall()
+	}
+
 	if !p.expepectedToken(l.LBRACE) {
 		// syntax error: missing left brace
 		return nil
@@ -232,12 +551,27 @@ func (p *Parser) parseMap() ast.Expression {
 	p.advanceTokens()
 	keyVal := p.parseKeyValues()
 	if keyVal != nil {
+		if p.peekToken.Token_type == l.FOR {
+			// we have a map comprehension like mapa{x => x * 2 por (x en valores)}
+			iteration, condition := p.parseComprehension()
+			if iteration == nil || !p.expepectedToken(l.RBRACE) {
+				return nil
+			}
+
+			return ast.NewMapComprehension(token, keyVal, iteration, condition)
+		}
+
 		keyValues = append(keyValues, keyVal)
 	}
 
 	// we loop untile we dont have commas. this means we parse all the key value pairs.
 	for p.peekToken.Token_type == l.COMMA {
 		p.advanceTokens()
+		if p.peekToken.Token_type == l.RBRACE {
+			// trailing comma like mapa{1 => 2,}
+			break
+		}
+
 		p.advanceTokens()
 		keyVal := p.parseKeyValues()
 		if keyVal != nil {
@@ -253,6 +587,12 @@ func (p *Parser) parseMap() ast.Expression {
 func (p *Parser) parseClassMethod() ast.Expression {
 	p.checkCurrentTokenIsNotNil()
 	token := p.currentToken
+	if token.Token_type != l.IDENT {
+		message := fmt.Sprintf("se esperaba el nombre de un metodo pero se obtuvo %s", token.Literal)
+		p.errors = append(p.errors, message)
+		return nil
+	}
+
 	name := p.parseIdentifier().(*ast.Identifier)
 	if !p.expepectedToken(l.LPAREN) {
 		return nil
@@ -303,7 +643,22 @@ func (p *Parser) parseImportStatement() ast.Stmt {
 	return ast.NewImportStatement(token, path)
 }
 
+// parse a try expression, the block form intentar {} excepto(e) {} or the
+// expression form intentar(x)
 func (p *Parser) parseTryExp() ast.Expression {
+	if p.peekToken.Token_type == l.LPAREN {
+		// we have the expression form like intentar(entero(x))
+		token := p.currentToken
+		p.advanceTokens()
+		p.advanceTokens()
+		value := p.parseExpression(LOWEST)
+		if value == nil || !p.expepectedToken(l.RPAREN) {
+			return nil
+		}
+
+		return ast.NewTryValue(token, value)
+	}
+
 	try := ast.NewTry(p.currentToken, nil, nil, nil)
 	if !p.expepectedToken(l.LBRACE) {
 		return nil
@@ -357,3 +712,173 @@ func (p *Parser) ParseTrhowExp() ast.Expression {
 
 	return ast.NewThrowExpression(token, message[0])
 }
+
+// parse a pattern matching expression like:
+//		coincidir valor { [a, b] => a + b; _ => 0 }
+func (p *Parser) parseMatch() ast.Expression {
+	p.checkCurrentTokenIsNotNil()
+	token := p.currentToken
+	p.advanceTokens()
+	value := p.parseExpression(LOWEST)
+	if value == nil || !p.expepectedToken(l.LBRACE) {
+		// syntax error -> coincidir valor [a, b] => a
+		return nil
+	}
+
+	arms := make([]*ast.MatchArm, 0)
+	p.advanceTokens()
+	for p.currentToken.Token_type != l.RBRACE {
+		if p.currentToken.Token_type == l.EOF {
+			p.unexpectedEOFError("}")
+			return nil
+		}
+
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+
+		arms = append(arms, arm)
+		p.advanceTokens()
+		// the arms can be separated by semicolons or commas
+		for p.currentToken.Token_type == l.SEMICOLON || p.currentToken.Token_type == l.COMMA {
+			p.advanceTokens()
+		}
+	}
+
+	return ast.NewMatch(token, value, arms)
+}
+
+// parse an arm of a coincidir expression with an optional guard like
+// x si (x > 10) => x, the body can be a block or an expression
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	token := p.currentToken
+	pattern := p.parsePattern()
+	if pattern == nil {
+		return nil
+	}
+
+	var guard ast.Expression = nil
+	if p.peekToken.Token_type == l.IF {
+		p.advanceTokens()
+		if !p.expepectedToken(l.LPAREN) {
+			// syntax error -> x si x > 10 => x
+			return nil
+		}
+
+		p.advanceTokens()
+		guard = p.parseExpression(LOWEST)
+		if guard == nil || !p.expepectedToken(l.RPAREN) {
+			return nil
+		}
+	}
+
+	if !p.expepectedToken(l.ARROW) {
+		return nil
+	}
+
+	p.advanceTokens()
+	if p.currentToken.Token_type == l.LBRACE {
+		return ast.NewMatchArm(token, pattern, guard, p.parseBlock())
+	}
+
+	exp := p.parserExpressionStatement()
+	return ast.NewMatchArm(token, pattern, guard, ast.NewBlock(exp.Token, exp))
+}
+
+// parse the pattern of a coincidir arm, the literals are matched by value, the
+// identifiers bind the matched value and _ matches anything
+func (p *Parser) parsePattern() ast.Expression {
+	p.checkCurrentTokenIsNotNil()
+	switch p.currentToken.Token_type {
+	case l.LBRACKET:
+		return p.parseListPattern()
+
+	case l.DATASTRCUT:
+		if !p.expepectedToken(l.LBRACKET) {
+			return nil
+		}
+		return p.parseListPattern()
+
+	case l.MAP:
+		if !p.expepectedToken(l.LBRACE) {
+			return nil
+		}
+		return p.parseMapPattern()
+
+	case l.IDENT:
+		return p.parseIdentifier()
+
+	case l.INT, l.FLOAT, l.STRING, l.CHAR, l.TRUE, l.FALSE, l.NULLT:
+		return p.prefixParsFns[p.currentToken.Token_type]()
+
+	case l.MINUS:
+		// negative numbers like -1
+		return p.parsePrefixExpression()
+
+	case l.EOF:
+		p.unexpectedEOFError("un patron")
+		return nil
+
+	default:
+		p.errors = append(p.errors, fmt.Sprintf("patron no valido: %s", p.currentToken.Literal))
+		return nil
+	}
+}
+
+// parse a list pattern like [a, 2, _], the current token is the left bracket
+func (p *Parser) parseListPattern() ast.Expression {
+	token := p.currentToken
+	elements := make([]ast.Expression, 0)
+	for p.peekToken.Token_type != l.RBRACKET {
+		p.advanceTokens()
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+
+		elements = append(elements, element)
+		if p.peekToken.Token_type != l.COMMA {
+			break
+		}
+		p.advanceTokens()
+	}
+
+	if !p.expepectedToken(l.RBRACKET) {
+		return nil
+	}
+
+	return ast.NewListPattern(token, elements...)
+}
+
+// parse a map pattern like mapa{"nombre" => n}, the current token is the left brace
+func (p *Parser) parseMapPattern() ast.Expression {
+	token := p.currentToken
+	pairs := make([]*ast.KeyValue, 0)
+	for p.peekToken.Token_type != l.RBRACE {
+		p.advanceTokens()
+		keyToken := p.currentToken
+		key := p.parseExpression(LOWEST)
+		if key == nil || !p.expepectedToken(l.ARROW) {
+			return nil
+		}
+
+		p.advanceTokens()
+		value := p.parsePattern()
+		if value == nil {
+			return nil
+		}
+
+		pairs = append(pairs, ast.NewKeyVal(keyToken, key, value))
+		if p.peekToken.Token_type != l.COMMA {
+			break
+		}
+		p.advanceTokens()
+	}
+
+	if !p.expepectedToken(l.RBRACE) {
+		return nil
+	}
+
+	return ast.NewMapPattern(token, pairs...)
+}
@@ -81,7 +81,7 @@ func StartRpl() {
 			continue
 		}
 
-		evaluated := evaluator.Evaluate(program, env)
+		evaluated := evaluator.Run(program, env)
 		if strings.Contains(scanned[len(scanned)-1], "escribir") {
 			scanned = scanned[:len(scanned)-1] // avoid to call the previus print
 		}
@@ -89,7 +89,7 @@ func StartRpl() {
 		if evaluated != nil && evaluated != obj.SingletonNUll {
 			writer.WriteString(evaluated.Inspect() + "\n")
 			writer.Flush()
-			if _, isError := evaluated.(*obj.Error); isError {
+			if err, isError := evaluated.(*obj.Error); isError && !err.Handled {
 				scanned = scanned[:len(scanned)-1] // delete error in scanned array
 			}
 		}
@@ -1,11 +1,19 @@
 package test
 
 import (
+	"aura/src/ast"
 	"aura/src/evaluator"
+	"bytes"
 	l "aura/src/lexer"
 	obj "aura/src/object"
 	p "aura/src/parser"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -54,12 +62,33 @@ func (e *EvaluatorTests) TestFloatEvaluation() {
 		{"5.5 *= 5.5", 30.25},
 		{"5.5 / 2", 2.75},
 		{"x := 10; x /= 3.2", 3.125},
+		{"x := 1; x += 1.5; x", 2.5},
+		{"x := 2.5; x -= 1; x", 1.5},
+		{"a := lista[1]; a[0] += 0.5; a[0]", 1.5},
+		{"10 / 2.5", 4},
+		{"3 * 1.5", 4.5},
 	}
 
 	for _, test := range tests {
 		evaluated := e.evaluateTests(test.source)
 		e.testFloatObject(evaluated, test.expected)
 	}
+
+	comparisons := []tuple[bool]{
+		{"2 >= 2.0", true},
+		{"1.5 < 2", true},
+		{"2.5 <= 2.4", false},
+		{`"a" <= "b"`, true},
+		{`"b" >= "c"`, false},
+	}
+
+	for _, test := range comparisons {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests("0 / 0.0")
+	e.testErrorObject(evaluated, "Division entre 0")
 }
 
 func (e *EvaluatorTests) TestArrayEvaluation() {
@@ -154,6 +183,37 @@ func (e *EvaluatorTests) TestArrayEvaluation() {
 	}
 }
 
+func (e *EvaluatorTests) TestComprehensions() {
+	tests := []tuple[interface{}]{
+		{source: "lista[x * 2 por (x en rango(4))];", expected: []int{0, 2, 4, 6}},
+		{source: "lista[x por (x en lista[-1, 2, -3, 4]) si (x > 0)];", expected: []int{2, 4}},
+		{source: "lista[x por (x en lista[1, 2]) si (x > 5)];", expected: []int{}},
+		{source: `lista[c + c por (c en "ab")];`, expected: []string{"aa", "bb"}},
+		{source: "m := mapa{x => x * x por (x en rango(5))}; m[3];", expected: 9},
+		{source: "m := mapa{x => x por (x en rango(10)) si (x % 2 == 0)}; largo(m);", expected: 5},
+		{source: "lista[x por (x en 5)];", expected: "No es un iteralble: 5"},
+		{source: "lista[x + verdadero por (x en lista[1])];", expected: "Discrepancia de tipos: entero + booleano"},
+		{source: "mapa{x + verdadero => x por (x en lista[1])};", expected: "Discrepancia de tipos: entero + booleano"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case []int:
+			e.testIntArrayObject(evaluated, expected)
+
+		case []string:
+			e.testStringArrayObject(evaluated, expected)
+
+		case string:
+			e.testErrorObject(evaluated, expected)
+
+		default:
+			e.testIntegerObject(evaluated, test.expected.(int))
+		}
+	}
+}
+
 func (e *EvaluatorTests) TestBangOperator() {
 	tests := []tuple[bool]{
 		{"!verdadero", false},
@@ -244,6 +304,12 @@ func (e *EvaluatorTests) TestIfElseEvaluation() {
 		{"si (4 > 2 && 5 > 10) { 10 } si_no { 20 }", 20},
 		{"si (4 > 2 || 5 > 10) { 10 } si_no { 20 }", 10},
 		{"si (4 < 2 || 5 > 10) { 10 } si_no { 20 }", 20},
+		{"si (1 > 2) { 10 } si_no si (2 > 1) { 20 } si_no { 30 }", 20},
+		{"si (1 > 2) { 10 } si_no si (2 > 3) { 20 } si_no { 30 }", 30},
+		{"si (1 > 2) { 10 } si_no si (2 > 3) { 20 }", nil},
+		{"si (1 < 2) { 10 } si_no si (x) { 20 }", 10},
+		{"x := 0; si (x > 0) { 1 } si_no si (x < 0) { 2 } si_no si (x == 0) { 3 } si_no { 4 }", 3},
+		{"var signo = funcion(n) { si (n > 0) { 1 } si_no si (n < 0) { -1 } si_no { 0 } }; signo(-5);", -1},
 	}
 
 	for _, test := range tests {
@@ -287,6 +353,10 @@ func (e *EvaluatorTests) TestErrorhandling() {
 		{source: "5 + verdadero; 9;", expected: "Discrepancia de tipos: entero + booleano"},
 		{source: "-verdadero", expected: "Operador desconocido: -booleano"},
 		{source: "verdadero + falso", expected: "Operador desconocido: booleano + booleano"},
+		{source: "verdadero > falso", expected: "Operador desconocido: booleano > booleano"},
+		{source: "falso < verdadero", expected: "Operador desconocido: booleano < booleano"},
+		{source: "verdadero >= verdadero", expected: "Operador desconocido: booleano >= booleano"},
+		{source: "falso <= 1", expected: "Discrepancia de tipos: booleano <= entero"},
 		{source: "5; verdadero - falso; 10;", expected: "Operador desconocido: booleano - booleano"},
 		{source: `
 			si (10 > 7) {
@@ -305,6 +375,21 @@ func (e *EvaluatorTests) TestErrorhandling() {
 			expected: "Operador desconocido: booleano / booleano",
 		},
 		{source: "foobar;", expected: "Identificador no encontrado: foobar"},
+		{source: "x = 5;", expected: "variable no declarada: x"},
+		{source: "var x = 5; x();", expected: "5 no es una funcion"},
+		{source: `var x = "hola"; x(1);`, expected: "hola no es una funcion"},
+		{source: "nulo();", expected: "nulo no es una funcion"},
+		{source: "desconocido();", expected: "Identificador no encontrado: desconocido"},
+		{source: "var suma = funcion(a, b) { a + b; }; suma(1);", expected: "se esperaban 2 argumentos pero se recibieron 1"},
+		{source: "var suma = funcion(a, b) { a + b; }; suma(1, 2, 3);", expected: "se esperaban 2 argumentos pero se recibieron 3"},
+		{source: "var cero = funcion() { 0; }; cero(1);", expected: "se esperaban 0 argumentos pero se recibieron 1"},
+		{source: "lista[1, 1 / 0, 3];", expected: "Division entre 0"},
+		{source: "var suma = funcion(a, b) { a + b; }; suma(1, 5 % 0);", expected: "Division entre 0"},
+		{source: `mapa{"a" => 1, "b" => 1 / 0};`, expected: "Division entre 0"},
+		{source: `mapa{desconocido => 1};`, expected: "Identificador no encontrado: desconocido"},
+		{source: "largo(lista[desconocido]);", expected: "Identificador no encontrado: desconocido"},
+		{source: "clase Punto(x, y) { suma() { regresa x + y; } } p := nuevo Punto(1);", expected: "se esperaban 2 argumentos pero se recibieron 1"},
+		{source: "var total = 0; totl = 5;", expected: "variable no declarada: totl"},
 		{source: `"foo" - "bar";`, expected: "Operador desconocido: texto - texto"},
 	}
 
@@ -316,6 +401,50 @@ func (e *EvaluatorTests) TestErrorhandling() {
 	}
 }
 
+func (e *EvaluatorTests) TestErrorPropagation() {
+	tests := []tuple[[]int]{
+		{`l := lista[]; intentar { l:agregar(1); 1 / 0; l:agregar(2); } excepto(e) { l:agregar(3); }; l;`, []int{1, 3}},
+		{`l := lista[]; intentar { por (i en rango(3)) { l:agregar(i); 1 / 0; } } excepto(e) {}; l;`, []int{0}},
+		{`l := lista[]; intentar { mientras (verdadero) { l:agregar(1); 1 / 0; } } excepto(e) {}; l;`, []int{1}},
+		{`l := lista[]; intentar { si (1 / 0 == 0) { l:agregar(1); } } excepto(e) { l:agregar(2); }; l;`, []int{2}},
+		{`l := lista[]; intentar { var x = 1 / 0; l:agregar(1); } excepto(e) {}; l;`, []int{}},
+		{`l := lista[]; intentar { -(1 / 0); l:agregar(1); } excepto(e) {}; l;`, []int{}},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntArrayObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests(`l := lista[]; 1 / 0; l:agregar(1);`)
+	e.testErrorObject(evaluated, "Division entre 0")
+
+	evaluated = e.evaluateTests(`x := 5; 1 + (x / 0) * 2;`)
+	e.testErrorObject(evaluated, "Division entre 0")
+
+	evaluated = e.evaluateTests(`var f = funcion() { regresa 1 / 0; }; f() + 1;`)
+	e.testErrorObject(evaluated, "Division entre 0")
+
+	// the rigth operand of && and || is only evaluated when it is needed
+	guards := []tuple[bool]{
+		{`var l = lista[1]; falso && l[5] == 1;`, false},
+		{`var l = lista[1]; verdadero || l[5] == 1;`, true},
+		{`var l = lista[1]; var i = 3; i < largo(l) && l[i] > 0;`, false},
+		{`var l = lista[1]; verdadero && l[0] == 1;`, true},
+	}
+
+	for _, test := range guards {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+
+	evaluated = e.evaluateTests(`var l = lista[1]; verdadero && l[5] == 1;`)
+	e.testErrorObject(evaluated, "indice 5 fuera de rango (longitud 1)")
+
+	evaluated = e.evaluateTests(`(1 / 0) ? 1 : 2;`)
+	e.testErrorObject(evaluated, "Division entre 0")
+}
+
 func (e *EvaluatorTests) TestAssingmentEvaluation() {
 	tests := []tuple[int]{
 		{"var a = 5; a;", 5},
@@ -323,6 +452,7 @@ func (e *EvaluatorTests) TestAssingmentEvaluation() {
 		{"var a = 5; var b = a; b;", 5},
 		{"var a = 5; var b = a; var c = a + b + 5; c;", 15},
 		{"a := 5; b := a; c := a + b + 5; c;", 15},
+		{"var niño = 5; var año = niño * 2; año;", 10},
 	}
 
 	for _, test := range tests {
@@ -352,6 +482,10 @@ func (e *EvaluatorTests) TestMaps() {
 		{`m := mapa{"a" => 1, "b" => 2}; m["b"]`, 2},
 		{`m := mapa{1 => "hola", 2 => "mundo"}; m[1]`, "hola"},
 		{`m := mapa{1 => "hola", 2 => " mundo"}; m[1] + m[2]`, "hola mundo"},
+		{`m := mapa{1 => "numero", "1" => "texto"}; m[1]`, "numero"},
+		{`m := mapa{1 => "numero", "1" => "texto"}; m["1"]`, "texto"},
+		{`m := mapa{lista[1] => "numeros", lista["1"] => "textos"}; m[lista["1"]]`, "textos"},
+		{`m := mapa{}; m[1] = 1; m["1"] = 2; largo(m)`, 2},
 	}
 
 	for _, test := range tests {
@@ -364,11 +498,208 @@ func (e *EvaluatorTests) TestMaps() {
 	}
 }
 
+func (e *EvaluatorTests) TestMapReassigment() {
+	tests := []tuple[string]{
+		{`m := mapa{"a" => 1}; m["b"] = 2; m;`, "{a => 1, b => 2}"},
+		{`m := mapa{"a" => 1}; m["a"] = 2; m["a"] = 3; m;`, "{a => 3}"},
+		{`m := mapa{1 => 1}; m[1] = 2; m[2] = 3; m;`, "{1 => 2, 2 => 3}"},
+		{`m := mapa{verdadero => 1}; m[verdadero] = 2; m[falso] = 3; m;`, "{verdadero => 2, falso => 3}"},
+		{`m := mapa{1.5 => 1}; m[1.5] = 2; m;`, "{1.5 => 2}"},
+		{`m := mapa{1 => "a"}; m["1"] = "b"; m[1] = "c"; m;`, "{1 => c, 1 => b}"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		if !e.IsType(&obj.Map{}, evaluated) {
+			e.T().FailNow()
+		}
+
+		e.Equal(test.expected, evaluated.Inspect())
+	}
+
+	evaluated := e.evaluateTests(`m := mapa{}; m[desconocido] = 1;`)
+	e.testErrorObject(evaluated, "Identificador no encontrado: desconocido")
+
+	evaluated = e.evaluateTests(`m := mapa{}; m["a"] = 1 + "b";`)
+	e.IsType(&obj.Error{}, evaluated)
+}
+
+func (e *EvaluatorTests) TestIndexReadWriteSymmetry() {
+	tests := []tuple[string]{
+		{"l := lista[1, 2, 3]; l[0] = 9; l[0];", "9"},
+		{"l := lista[1, 2, 3]; l[-1] = 9; l[-1];", "9"},
+		{"l := lista[1, 2, 3]; l[-3] = 9; l;", "[9, 2, 3]"},
+		{"l := lista[1, 2, 3]; l[3];", "Error: indice 3 fuera de rango (longitud 3)"},
+		{"l := lista[1, 2, 3]; l[3] = 9;", "Error: indice 3 fuera de rango (longitud 3)"},
+		{"l := lista[1, 2, 3]; l[-4];", "Error: indice -4 fuera de rango (longitud 3)"},
+		{"l := lista[1, 2, 3]; l[-4] = 9;", "Error: indice -4 fuera de rango (longitud 3)"},
+		{`l := lista[1, 2, 3]; l["a"];`, "Error: el indice debe ser un entero, se obtuvo texto"},
+		{`l := lista[1, 2, 3]; l["a"] = 9;`, "Error: el indice debe ser un entero, se obtuvo texto"},
+		{"l := lista[1, 2, 3]; l[x];", "Error: Identificador no encontrado: x"},
+		{"l := lista[1, 2, 3]; l[x] = 9;", "Error: Identificador no encontrado: x"},
+		{`s := "año"; s[1];`, "ñ"},
+		{`s := "año"; s[-1];`, "o"},
+		{`s := "año"; s[3];`, "Error: indice 3 fuera de rango (longitud 3)"},
+		{`s := "año"; s[1.5];`, "Error: el indice debe ser un entero, se obtuvo flotante"},
+		{"l := lista[1, 2, 3]; l[5] = 9;", "Error: indice 5 fuera de rango (longitud 3)"},
+		{"l := lista[1, 2, 3]; l[5] = 9; l;", "Error: indice 5 fuera de rango (longitud 3)"},
+		{"l := lista[]; l[0] = 9;", "Error: indice 0 fuera de rango (longitud 0)"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect(), test.source)
+	}
+}
+
+func (e *EvaluatorTests) TestMethodOnIndexResult() {
+	tests := []tuple[string]{
+		{`datos := mapa{"lista" => lista[1, 2]}; datos["lista"]:contiene(2);`, "verdadero"},
+		{"m := lista[lista[1, 2], lista[3]]; m[0]:map(|x| => x * 2);", "[2, 4]"},
+		{"m := lista[lista[1, 2], lista[3]]; m[-1]:contiene(1);", "falso"},
+		{`s := lista["hola"]; s[0]:mayusculas();`, "HOLA"},
+		{`"hola"[0]:mayusculas();`, "H"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect(), test.source)
+	}
+}
+
+func (e *EvaluatorTests) TestMethodsNotShadowedByVariables() {
+	tests := []tuple[string]{
+		{`var indice = 1; lista[5, 6]:indice(6);`, "1"},
+		{`contiene := falso; "hola":contiene("h");`, "verdadero"},
+		{`mayusculas := 0; "a":mayusculas() + texto(mayusculas);`, "A0"},
+		{`funcion map(l) { regresa 0 } lista[1, 2]:map(|x| => x * 2);`, "[2, 4]"},
+		{`funcion separar(s) { regresa s } "a b":separar(" ");`, "[a, b]"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect(), test.source)
+	}
+}
+
+func (e *EvaluatorTests) TestMapExpressionKeys() {
+	tests := []tuple[interface{}]{
+		{`nombre := "ana"; m := mapa{nombre => 1, 2 + 3 => "cinco"}; m["ana"];`, 1},
+		{`nombre := "ana"; m := mapa{nombre => 1, 2 + 3 => "cinco"}; m[5];`, "cinco"},
+		{`m := mapa{lista[1, 1 + 1] => "l"}; m[lista[1, 2]];`, "l"},
+		{`k := 2; m := mapa{k * 2 => "cuatro"}; m[4];`, "cuatro"},
+		{`m := mapa{"a" + "b" => 1}; m:contiene("ab");`, true},
+		{`mapa{1 => "a", 0 + 1 => "b"};`, "la llave ya existe en el mapa"},
+		{`mapa{y => 1};`, "Identificador no encontrado: y"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case bool:
+			e.testBooleanObject(evaluated, expected)
+		default:
+			if _, isErr := evaluated.(*obj.Error); isErr {
+				e.testErrorObject(evaluated, expected.(string))
+			} else {
+				e.testStringObject(evaluated, expected.(string))
+			}
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestMapMissingKey() {
+	source := `m := mapa{"a" => 1, "nada" => nulo};`
+	tests := []tuple[string]{
+		{`m["a"];`, "1"},
+		{`m["nada"];`, "nulo"},
+		{`m["noexiste"];`, "nulo"},
+		{`m["noexiste"] == nulo;`, "verdadero"},
+		{`m:contiene("nada");`, "verdadero"},
+		{`m:contiene("noexiste");`, "falso"},
+		{`m["noexiste"]; largo(m);`, "2"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(source + test.source)
+		e.Equal(test.expected, evaluated.Inspect(), test.source)
+	}
+}
+
+func (e *EvaluatorTests) TestMapNumericKeys() {
+	tests := []tuple[string]{
+		{`m := mapa{1 => "a", 2 => "b"}; m[3] = "c"; m;`, "{1 => a, 2 => b, 3 => c}"},
+		{`m := mapa{1 => "a", 2 => "b"}; m[2];`, "b"},
+		// the numbers are compared by value, so 1.0 is the same key as 1
+		{`m := mapa{1 => "a"}; m[1.0];`, "a"},
+		{`m := mapa{2.0 => "a"}; m[2];`, "a"},
+		{`m := mapa{1 => "a"}; m[1.0] = "b"; m;`, "{1 => b}"},
+		{`m := mapa{1 => "a", 1.5 => "b"}; largo(m);`, "2"},
+		{`m := mapa{1 => "a", 1.5 => "b"}; m[1.5];`, "b"},
+		{`m := mapa{lista[1, 2] => "a"}; m[lista[1.0, 2.0]];`, "a"},
+		{`conjunto(lista[1, 1.0, 2, 2.5]);`, "conjunto{1, 2, 2.5}"},
+		// the floats bigger than any integer are different keys
+		{`m := mapa{1e300 => "a", 1e301 => "b"}; largo(m);`, "2"},
+		{`m := mapa{1e300 => "a", 1e301 => "b"}; m[1e300] + m[1e301];`, "ab"},
+		{`m := mapa{-1e300 => "a", 1e300 => "b"}; m[-1e300];`, "a"},
+		{`conjunto(lista[1e19, 2e19, 1e19]);`, "conjunto{1e+19, 2e+19}"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect(), test.source)
+	}
+
+	evaluated := e.evaluateTests(`mapa{1 => "a", 1.0 => "b"};`)
+	e.IsType(&obj.Error{}, evaluated)
+}
+
+func (e *EvaluatorTests) TestMapInspectOrder() {
+	tests := []tuple[string]{
+		{`mapa{"b" => 2, "a" => 1, "c" => 3};`, "{b => 2, a => 1, c => 3}"},
+		{`m := mapa{}; m["c"] = 3; m["a"] = 1; m["b"] = 2; m;`, "{c => 3, a => 1, b => 2}"},
+		{`m := mapa{"a" => 1, "b" => 2}; m["a"] = 3; m;`, "{a => 3, b => 2}"},
+		{`mapa{x => x * 2 por (x en lista[3, 1, 2])};`, "{3 => 6, 1 => 2, 2 => 4}"},
+	}
+
+	for _, test := range tests {
+		// the representation must not change between evaluations
+		for i := 0; i < 20; i++ {
+			evaluated := e.evaluateTests(test.source)
+			e.Equal(test.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestCircularReferences() {
+	tests := []tuple[string]{
+		{`var a = lista[1]; a[0] = a; a;`, "[[...]]"},
+		{`var a = lista[1, 2]; a:agregar(a); a;`, "[1, 2, [...]]"},
+		{`var m = mapa{"x" => 1}; m["yo"] = m; m;`, "{x => 1, yo => {...}}"},
+		{`var a = lista[]; var b = lista[a]; a:agregar(b); a;`, "[[[...]]]"},
+		{`var a = lista[1]; lista[a, a];`, "[[1], [1]]"},
+		{`var a = lista[1]; a:agregar(a); var m = mapa{a => "si"}; m[a];`, "si"},
+		{`var a = lista[1]; a:agregar(a); inspeccionar(a);`, "lista(2)[\n  1(entero),\n  lista(2)[...]\n]"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect(), test.source)
+	}
+}
+
 func (e *EvaluatorTests) TestMapMethods() {
 	tests := []tuple[interface{}]{
 		{`m := mapa{"a" => 1, "b" => 2}; m:contiene("a");`, true},
 		{`m := mapa{"a" => 1, "b" => 2}; m:contiene("b");`, true},
 		{`m := mapa{"a" => 1, "b" => 2}; m:contiene("d");`, false},
+		{`m := mapa{1 => 1}; m:contiene("1");`, false},
+		{`m := mapa{"1" => 1}; m:contiene("1");`, true},
+		{`m := mapa{"a" => nulo}; m:contiene("a");`, true},
+		{`m := mapa{}; m["a"] = nulo; m:contiene("a");`, true},
+		{`m := mapa{"a" => nulo}; m:contiene("b");`, false},
 		{`m := mapa{"a" => 1, "b" => 2}; m:valores();`, []int{1, 2}},
 	}
 
@@ -380,6 +711,9 @@ func (e *EvaluatorTests) TestMapMethods() {
 			e.testIntArrayObject(evaluated, test.expected.([]int))
 		}
 	}
+
+	e.Equal("nulo", e.evaluateTests(`m := mapa{"a" => nulo}; m["a"];`).Inspect())
+	e.testErrorObject(e.evaluateTests(`m := mapa{}; m[x];`), "Identificador no encontrado: x")
 }
 
 func (e *EvaluatorTests) TestStringMethods() {
@@ -437,12 +771,123 @@ func (e *EvaluatorTests) TestForLoop() {
 		{`i := 0; por(n en rango(4)) { i++; }; i;`, 4},
 		{`i := 0; por(n en rango(5, 10)) {i += n}; i;`, 35},
 		{`i := 0; j := "hola"; por(k en j) { i++; }; i;`, 4},
+		{`i := 0; por(n en lista[]) { i++; }; i;`, 0},
+		{`i := 0; por(n en "") { i++; }; i;`, 0},
+		{`i := 0; por(n en lista[3, 4, 5]) { i += n; }; i;`, 12},
+		{`i := 0; m := mapa{1 => "a", 2 => "b", 3 => "c"}; por(k en m) { i += k; }; i;`, 6},
+		{`i := 0; m := mapa{1 => 10, 2 => 20}; por(k en m) { i += m[k]; }; i;`, 30},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntegerObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestForLoopIterables() {
+	tests := []tuple[[]int]{
+		{`x := lista[]; por(n en rango(3)) { x:agregar(n); }; x;`, []int{0, 1, 2}},
+		{`x := lista[]; por(n en rango(5, 0)) { x:agregar(n); }; x;`, []int{5, 4, 3, 2, 1}},
+		{`x := lista[]; por(n en lista[7, 8, 9]) { x:agregar(n); }; x;`, []int{7, 8, 9}},
+		{`x := lista[]; por(n en mapa{3 => "a", 1 => "b"}) { x:agregar(n); }; x;`, []int{3, 1}},
+		{`x := lista[]; por(c en "123") { x:agregar(entero(c)); }; x;`, []int{1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntArrayObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestClassicFor() {
+	tests := []tuple[int]{
+		{`suma := 0; por (var i = 0; i < 4; i++) { suma += i; }; suma;`, 6},
+		{`suma := 0; por (i := 10; i > 0; i -= 4) { suma += i; }; suma;`, 18},
+		{`suma := 0; por (var i = 0; i < 5; i++) { si (i == 3) { romper; } suma += i; }; suma;`, 3},
+		{`suma := 0; por (var i = 0; i < 5; i++) { si (i % 2 == 0) { continuar; } suma += i; }; suma;`, 4},
+		{`suma := 0; por (var i = 0; ; i++) { si (i == 3) { romper; } suma += 1; }; suma;`, 3},
+		{`suma := 0; por (var i = 0; i < 0; i++) { suma += 1; }; suma;`, 0},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntegerObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests(`x := lista[]; por (var i = 0; i < 3; i++) { x:agregar(i * 10); }; x;`)
+	e.testIntArrayObject(evaluated, []int{0, 10, 20})
+
+	evaluated = e.evaluateTests(`por (var i = 0; i < 3; i++) { i + "a"; }`)
+	e.IsType(&obj.Error{}, evaluated)
+}
+
+func (e *EvaluatorTests) TestLoopScopes() {
+	tests := []tuple[[]int]{
+		// each iteration of a range loop has its own variable
+		{`fs := lista[]; por (i en rango(3)) { fs:agregar(|x| => x + i); }; r := lista[]; por (f en fs) { r:agregar(f(0)); }; r;`, []int{0, 1, 2}},
+		{`fs := lista[]; por (i en rango(3)) { var y = i * 10; fs:agregar(|x| => x + y); }; r := lista[]; por (f en fs) { r:agregar(f(0)); }; r;`, []int{0, 10, 20}},
+		// the classic for shares its variable so the closures see the final value
+		{`fs := lista[]; por (var i = 0; i < 3; i++) { fs:agregar(|x| => x + i); }; r := lista[]; por (f en fs) { r:agregar(f(0)); }; r;`, []int{3, 3, 3}},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntArrayObject(evaluated, test.expected)
+	}
+
+	outer := []tuple[int]{
+		{`suma := 0; por (i en rango(4)) { suma = suma + i; }; suma;`, 6},
+		{`i := 0; mientras (i < 3) { i = i + 1; }; i;`, 3},
+		// the body of mientras shares the scope of the loop
+		{`i := 0; mientras (i < 3) { i := i + 1; }; i;`, 3},
+	}
+
+	for _, test := range outer {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntegerObject(evaluated, test.expected)
+	}
+
+	// an integer variable that becomes a float is updated where it was declared
+	floats := []tuple[float64]{
+		{`var x = 1; mientras (x < 2) { x += 0.5; }; x;`, 2},
+		{`var x = 1; por (i en rango(2)) { x += 0.5; }; x;`, 2},
+		{`var x = 1; var f = funcion() { x *= 1.5; }; f(); x;`, 1.5},
+	}
+
+	for _, test := range floats {
+		evaluated := e.evaluateTests(test.source)
+		e.IsType(&obj.Float{}, evaluated, test.source)
+		if float, isFloat := evaluated.(*obj.Float); isFloat {
+			e.Equal(test.expected, float.Value, test.source)
+		}
+	}
+
+	leaks := []string{
+		`por (i en rango(3)) { var z = i; }; z;`,
+		`por (i en rango(3)) { si (i == 1) { z; } var z = i; }`,
+	}
+
+	for _, source := range leaks {
+		evaluated := e.evaluateTests(source)
+		e.IsType(&obj.Error{}, evaluated)
+	}
+}
+
+func (e *EvaluatorTests) TestAssigmentInExpressions() {
+	tests := []tuple[int]{
+		{`i := 0; suma := 0; mientras ((n := i * 2) < 10) { suma += n; i++; }; suma;`, 20},
+		{`i := 0; mientras ((n := i * 2) < 10) { i++; }; n;`, 10},
+		{`si ((m := 3 + 4) == 7) { m; } si_no { 0; }`, 7},
+		{`x := (y := 5) + 1; x + y;`, 11},
 	}
 
 	for _, test := range tests {
 		evaluated := e.evaluateTests(test.source)
 		e.testIntegerObject(evaluated, test.expected)
 	}
+
+	evaluated := e.evaluateTests(`x := 1; (x := 1 + "a");`)
+	e.IsType(&obj.Error{}, evaluated)
 }
 
 func (e *EvaluatorTests) TestReassigment() {
@@ -457,6 +902,9 @@ func (e *EvaluatorTests) TestReassigment() {
 		{`b := mapa{"a" => 1, "b" => 2}; b["c"] = 32; b["c"];`, 32},
 		{`c := lista[2,3,4]; c[0] = 10; c[0]`, 10},
 		{`c := lista[2,3,4]; c[0] = 10; c[0] = 23; c[0]`, 23},
+		{`a := 1; por(n en rango(3)) { a = n; }; a;`, 2},
+		{`a := 1; var cambiar = funcion() { a = 7; }; cambiar(); a;`, 7},
+		{`a := 1; var cambiar = funcion(a) { a = 7; }; cambiar(3); a;`, 1},
 	}
 
 	for _, test := range tests {
@@ -474,10 +922,10 @@ func (e *EvaluatorTests) TestCallList() {
 		{"mi_lista := lista[1,23,4,5]; mi_lista[-1];", 5},
 		{"mi_lista := lista[1,23,4,5]; mi_lista[-2];", 4},
 		{"mi_lista := lista[1,23,4,5]; mi_lista[-3];", 23},
-		{"mi_lista := lista[1,23,4,5]; mi_lista[-5];", "Indice fuera de rango indice: -5, longitud: 4"},
+		{"mi_lista := lista[1,23,4,5]; mi_lista[-5];", "indice -5 fuera de rango (longitud 4)"},
 		{"mi_lista := lista[1,23,4,5]; mi_lista[3] + mi_lista[0];", 6},
 		{"mi_lista := lista[1,23,4,5]; mi_lista[1] + mi_lista[2];", 27},
-		{"mi_lista := lista[1,23,4,5]; mi_lista[100];", "Indice fuera de rango indice: 100, longitud: 4"},
+		{"mi_lista := lista[1,23,4,5]; mi_lista[100];", "indice 100 fuera de rango (longitud 4)"},
 	}
 
 	for _, test := range tests {
@@ -557,71 +1005,1085 @@ func (e *EvaluatorTests) TestClassEvaluation() {
 	}
 }
 
-func (e *EvaluatorTests) TestBuiltinFunctions() {
+func (e *EvaluatorTests) TestClassConstructor() {
 	tests := []tuple[interface{}]{
-		{source: `largo("");`, expected: 0},
-		{source: `largo("cuatro");`, expected: 6},
-		{source: `largo("hola mundo");`, expected: 10},
-		{source: "largo(1);", expected: "argumento para largo no valido, se recibio entero"},
-		{
-			source:   `largo("uno", "dos");`,
-			expected: "numero incorrecto de argumentos para largo, se recibieron 2, se requieren 1",
-		},
-		{source: "tipo(1);", expected: "entero"},
-		{source: "tipo(verdadero)", expected: "booleano"},
-		{source: `tipo("hello world")`, expected: "texto"},
-		{source: `entero("1")`, expected: 1},
-		{source: `entero("hola")`, expected: "No se puede parsear como entero hola"},
-		{source: "texto(12)", expected: "12"},
-		{source: "a := lista[2,3,4]; largo(a);", expected: 3},
-		{source: "a := lista[2,3,4]; tipo(a);", expected: "lista"},
-		{source: `b := mapa{"a" => 2}; largo(b);`, expected: 1},
-		{source: `b := mapa{"a" => 2}; tipo(b);`, expected: "mapa"},
-		{source: `b := mapa{"a" => 2}; tipo(b["a"]);`, expected: "entero"},
-		{source: `a := "aura"; a:contiene("a");`, expected: true},
-		{source: `a := "aura"; a:contiene("au");`, expected: true},
-		{source: `a := "aura"; a:contiene("ra");`, expected: true},
-		{source: `a := "aura"; a:contiene("ara");`, expected: false},
-		{source: `a := "aura"; a:contiene("b");`, expected: false},
-		{source: `a := lista[2,34,45]; a:contiene(2);`, expected: true},
-		{source: `a := lista[2,34,45]; a:contiene(342);`, expected: false},
-		{source: `formatear("hola soy {}", "dario");`, expected: "hola soy dario"},
-		{source: `formatear("hi im {}, i am {} years old", "dario", 19);`, expected: "hi im dario, i am 19 years old"},
-		{source: `abs(-1)`, expected: 1},
-		{source: "abs(1)", expected: 1},
-		{source: "abs(-2.5)", expected: 2.5},
-		{source: "abs(2.5)", expected: 2.5},
-		{source: `abs("h")`, expected: "argumento para abs no valido, se recibio texto"},
-		{source: `flotante(5)`, expected: 5.0},
-		{source: `flotante(10)`, expected: 10.0},
-		{source: `flotante("10.5")`, expected: 10.5},
-		{source: `flotante("5.5")`, expected: 5.5},
-		{source: `flotante("h")`, expected: "no se pudo parsear como flotante h"},
-		{source: "suma(lista[2,3,4])", expected: 9},
-	}
-
-	for _, test := range tests {
-		evaluated := e.evaluateTests(test.source)
-		switch val := test.expected.(type) {
-		case int:
-			e.testIntegerObject(evaluated, val)
+		{source: `
+			clase Perro(nombre, edad) {
+				iniciar() {
+					nombre = nombre:mayusculas();
+					edad = edad * 7;
+				}
 
-		case string:
-			if str, isStr := evaluated.(*obj.String); isStr {
-				e.testStringObject(str, val)
-			} else {
-				e.testErrorObject(evaluated, val)
+				describir() => formatear("{} tiene {}", nombre, edad);
 			}
 
-		case bool:
-			e.testBooleanObject(evaluated, val)
+			p := nuevo Perro("fido", 3);
+			p.describir();
+		`,
+			expected: "FIDO tiene 21",
+		},
+		{source: `
+			clase Perro(nombre, edad) {
+				iniciar() {
+					edad = edad + 1;
+				}
+			}
 
-		case float64:
-			e.testFloatObject(evaluated, val)
+			p := nuevo Perro("fido", 3);
+			p.edad = p.edad + 1;
+			p.edad;
+		`,
+			expected: 5,
+		},
+		{source: `
+			clase Perro(nombre) {
+				iniciar() {
+					si (nombre == "") {
+						lanzar Error("el nombre no puede estar vacio");
+					}
+				}
+			}
 
-		default:
-			panic("Some weird shit happend!")
-		}
+			nuevo Perro("");
+		`,
+			expected: "el nombre no puede estar vacio",
+		},
+		{source: `
+			clase Perro(nombre) {
+				iniciar(x) {
+					regresa x;
+				}
+			}
+
+			nuevo Perro("fido");
+		`,
+			expected: "se esperaban 1 argumentos pero se recibieron 0",
+		},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		if num, isInt := test.expected.(int); isInt {
+			e.testIntegerObject(evaluated, num)
+		} else if err, isErr := evaluated.(*obj.Error); isErr {
+			e.Equal(test.expected, err.Message)
+		} else {
+			e.testStringObject(evaluated, test.expected.(string))
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestInstanceMethodArguments() {
+	class := `
+		clase Cuenta(saldo) {
+			depositar(monto, veces) {
+				saldo = saldo + monto * veces;
+				regresa saldo;
+			}
+
+			doble() => este.depositar(saldo, 1);
+		}
+
+		c := nuevo Cuenta(10);
+	`
+
+	tests := []tuple[interface{}]{
+		{"c.depositar(5, 2);", 20},
+		{"saldo := 1; c.depositar(saldo, 3);", 13},
+		{"funcion f() { monto := 4; regresa c.depositar(monto, 1); } f();", 14},
+		{"c.doble();", 20},
+		{"funcion f(n) { c.saldo = n; } f(7); c.saldo;", 7},
+		{"c.retirar(1);", "la clase Cuenta no tiene la propiedad retirar"},
+		{"c.depositar(1);", "se esperaban 2 argumentos pero se recibieron 1"},
+		{"c.depositar(x, 1);", "Identificador no encontrado: x"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(class + test.source)
+		if num, isInt := test.expected.(int); isInt {
+			e.testIntegerObject(evaluated, num)
+		} else {
+			e.testErrorObject(evaluated, test.expected.(string))
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestSuffixAfterAnyExpression() {
+	tests := []tuple[int]{
+		{"a := lista[1, 2]; a[0]++; a[0];", 2},
+		{`m := mapa{"a" => 3}; m["a"]--; m["a"];`, 2},
+		{"clase C(campo) { f() => campo; } c := nuevo C(5); c.campo--; c.f();", 4},
+		{"(1 + 2)**;", 9},
+		{"x := 3; 2 * x++;", 8},
+		{"a := lista[1]; b := a[0]; b++; a[0];", 1},
+		{"x := 1; y := x; y += 2; x;", 1},
+		{"x := 5; -x; x;", 5},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntegerObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestFormatNumber() {
+	tests := []tuple[string]{
+		{`formato_numero(1234567.891, 2, ",");`, "1,234,567.89"},
+		{`formato_numero(1234567.891, 0, ",");`, "1,234,568"},
+		{`formato_numero(-1234567.891, 1, ",");`, "-1,234,567.9"},
+		{`formato_numero(1234567, 2, ".");`, "1.234.567,00"},
+		{`formato_numero(123, 0, ",");`, "123"},
+		{`formato_numero(0, 2, ",");`, "0.00"},
+		{`formato_numero(-0.001, 2, ",");`, "0.00"},
+		{`formato_numero(999.999, 2, " ");`, "1 000.00"},
+		{`formato_numero(1234567890123, 0, "_");`, "1_234_567_890_123"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testStringObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests(`formato_numero(1, -1, ",");`)
+	e.IsType(&obj.Error{}, evaluated)
+
+	evaluated = e.evaluateTests(`formato_numero("1", 2, ",");`)
+	e.testErrorObject(evaluated, "argumento para formato_numero no valido, se recibio texto")
+}
+
+func (e *EvaluatorTests) TestDateBuiltins() {
+	tests := []tuple[string]{
+		{`fecha(2024, 2, 28);`, "2024-02-28 00:00:00"},
+		{`fecha(2024, 2, 28, 13, 5, 9);`, "2024-02-28 13:05:09"},
+		{`sumar_dias(fecha(2024, 2, 28), 2);`, "2024-03-01 00:00:00"},
+		{`sumar_dias(fecha(2024, 1, 1), -1);`, "2023-12-31 00:00:00"},
+		{`sumar_segundos(fecha(2024, 1, 1), 3661);`, "2024-01-01 01:01:01"},
+		{`formato_fecha(fecha(2023, 1, 2, 3, 4, 5), "%d/%m/%Y %H:%M:%S");`, "02/01/2023 03:04:05"},
+		{`formato_fecha(fecha(2023, 1, 2), "100%% %Y");`, "100% 2023"},
+		{`tipo(ahora());`, "fecha"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		if date, isDate := evaluated.(*obj.Time); isDate {
+			e.Equal(test.expected, date.Inspect())
+			continue
+		}
+
+		e.testStringObject(evaluated, test.expected)
+	}
+
+	methods := []tuple[int]{
+		{`f := fecha(2024, 2, 28); f:anio();`, 2024},
+		{`f := fecha(2024, 2, 28); f:mes();`, 2},
+		{`f := fecha(2024, 2, 28); f:dia();`, 28},
+		{`f := fecha(2024, 2, 28); diferencia_segundos(f, sumar_dias(f, 1));`, 86400},
+		{`mes := 3; dia := 4; anio := 5; f := fecha(2024, 2, 28); f:mes() + f:dia() + mes;`, 33},
+		{`funcion dia(f) { regresa f:dia() * 2 } dia(fecha(2024, 2, 28));`, 56},
+	}
+
+	for _, test := range methods {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntegerObject(evaluated, test.expected)
+	}
+
+	// the methods of the dates are not global functions
+	evaluated := e.evaluateTests(`mes();`)
+	e.testErrorObject(evaluated, "Identificador no encontrado: mes")
+
+	evaluated = e.evaluateTests(`f := fecha(2024, 2, 28); f:mes(1);`)
+	e.testErrorObject(evaluated, "numero incorrecto de argumentos para mes, se recibieron 1, se requieren 0")
+
+	errors := []string{
+		`fecha(2024, 13, 1);`,
+		`fecha("2024", 1, 1);`,
+		`formato_fecha(fecha(2024, 1, 1), "%Q");`,
+		`sumar_dias(1, 1);`,
+	}
+
+	for _, source := range errors {
+		evaluated := e.evaluateTests(source)
+		e.IsType(&obj.Error{}, evaluated)
+	}
+}
+
+func (e *EvaluatorTests) TestSleep() {
+	start := time.Now()
+	evaluated := e.evaluateTests(`dormir(20);`)
+	e.testNullObject(evaluated)
+	e.GreaterOrEqual(time.Since(start), 20*time.Millisecond)
+	e.Less(time.Since(start), time.Second)
+
+	evaluated = e.evaluateTests(`dormir(-1);`)
+	e.testErrorObject(evaluated, "dormir no puede recibir milisegundos negativos: -1")
+
+	evaluated = e.evaluateTests(`dormir("1");`)
+	e.testErrorObject(evaluated, "argumento para dormir no valido, se recibio texto")
+
+	evaluated = e.evaluateTests(`dormir();`)
+	e.testErrorObject(evaluated, "numero incorrecto de argumentos para dormir, se recibieron 0, se requieren 1")
+}
+
+func (e *EvaluatorTests) TestRegexBuiltins() {
+	matches := []tuple[bool]{
+		{`coincide("^[0-9]+$", "12345");`, true},
+		{`coincide("^[0-9]+$", "12a45");`, false},
+		{`coincide("h.la", "dijo hola");`, true},
+	}
+
+	for _, test := range matches {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+
+	groups := []tuple[[]string]{
+		{`extraer("([a-z]+)@([a-z]+)\.com", "correo: ana@aura.com");`, []string{"ana", "aura"}},
+		{`extraer("[0-9]+", "tengo 42 años");`, []string{"42"}},
+		{`extraer("[0-9]+", "sin numeros");`, []string{}},
+	}
+
+	for _, test := range groups {
+		evaluated := e.evaluateTests(test.source)
+		e.testStringArrayObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests(`reemplazar_regex("([a-z]+)=([0-9]+)", "a=1, b=2", "$2=$1");`)
+	e.testStringObject(evaluated, "1=a, 2=b")
+
+	// more patterns than the cache keeps are compiled again when needed
+	evaluated = e.evaluateTests(`
+		total := 0;
+		por (vuelta en rango(2)) {
+			por (i en rango(300)) {
+				si (coincide("^${i}$", "${i}") && !coincide("^${i}$", "x")) {
+					total += 1;
+				}
+			}
+		}
+		total;
+	`)
+	e.testIntegerObject(evaluated, 600)
+
+	evaluated = e.evaluateTests(`coincide("(", "texto");`)
+	e.testErrorObject(evaluated, "patron no valido para coincide: (")
+
+	evaluated = e.evaluateTests(`extraer("a", 1);`)
+	e.testErrorObject(evaluated, "argumento para extraer no valido, se recibio entero")
+}
+
+func (e *EvaluatorTests) TestJSONBuiltins() {
+	types := []tuple[string]{
+		{`tipo(de_json("42"));`, "entero"},
+		{`tipo(de_json("42.5"));`, "flotante"},
+		{`tipo(de_json("42.0"));`, "entero"},
+		{`tipo(de_json("-7"));`, "entero"},
+		{`tipo(de_json("1e3"));`, "entero"},
+		{`tipo(de_json("1e30"));`, "flotante"},
+		{`tipo(de_json("[1, 2.5]")[0]);`, "entero"},
+		{`tipo(de_json("[1, 2.5]")[1]);`, "flotante"},
+	}
+
+	for _, test := range types {
+		evaluated := e.evaluateTests(test.source)
+		e.testStringObject(evaluated, test.expected)
+	}
+
+	// the integers from json can be used as indexes
+	evaluated := e.evaluateTests(`lista[10, 20, 30][de_json("2")];`)
+	e.testIntegerObject(evaluated, 30)
+
+	roundTrips := []tuple[string]{
+		{`de_json(a_json(lista[1, 2, 3]));`, "[1, 2, 3]"},
+		{`de_json(a_json(lista[1.5, -2.25]));`, "[1.5, -2.25]"},
+		{`de_json(a_json(mapa{"b" => 1, "a" => 2.5, "c" => lista[nulo, verdadero, "hola"]}));`, "{a => 2.5, b => 1, c => [nulo, verdadero, hola]}"},
+		{`a_json(mapa{"b" => 1, "a" => lista[2.5, falso, nulo], "c" => "ñ"});`, `{"a":[2.5,false,null],"b":1,"c":"ñ"}`},
+		{`a_json(de_json("[1, 2.5, 1e3]"));`, "[1,2.5,1000]"},
+	}
+
+	for _, test := range roundTrips {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect(), test.source)
+	}
+
+	// the same map built in different insertion orders gives the same json
+	sources := []string{
+		`var datos = mapa{"nombre" => "aura", "versiones" => lista[1, 2.5], "vacio" => lista[], "extra" => mapa{"activo" => verdadero, "beta" => falso}};`,
+		`var datos = mapa{"extra" => mapa{"beta" => falso, "activo" => verdadero}, "vacio" => lista[], "versiones" => lista[1, 2.5], "nombre" => "aura"};`,
+	}
+	formats := []tuple[string]{
+		{`a_json(datos);`, `{"extra":{"activo":true,"beta":false},"nombre":"aura","vacio":[],"versiones":[1,2.5]}`},
+		{`a_json(datos, falso);`, `{"extra":{"activo":true,"beta":false},"nombre":"aura","vacio":[],"versiones":[1,2.5]}`},
+		{`a_json(datos, verdadero);`, "{\n  \"extra\": {\n    \"activo\": true,\n    \"beta\": false\n  },\n  \"nombre\": \"aura\",\n  \"vacio\": [],\n  \"versiones\": [\n    1,\n    2.5\n  ]\n}"},
+		{`a_json(5, verdadero);`, "5"},
+	}
+
+	for _, source := range sources {
+		for _, test := range formats {
+			evaluated := e.evaluateTests(source + test.source)
+			e.testStringObject(evaluated, test.expected)
+		}
+	}
+
+	errors := []tuple[string]{
+		{`de_json("[1,");`, "json no valido para de_json en la posicion 3"},
+		{`de_json("");`, "json no valido para de_json: fin inesperado"},
+		{`de_json("1 2");`, "json no valido para de_json: contenido despues del valor"},
+		{`de_json(1);`, "argumento para de_json no valido, se recibio entero"},
+		{`a_json(mapa{1 => 2});`, "las llaves deben ser texto para a_json, se obtuvo entero"},
+		{`a_json(lista[1], 1);`, "argumento para a_json no valido, se recibio entero"},
+		{`var a = lista[1]; a:agregar(a); a_json(a);`, "no se puede convertir a json una referencia circular"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestHttpBuiltins() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, "%s %s", r.Header.Get("Content-Type"), body)
+			return
+		}
+
+		fmt.Fprint(w, "hola")
+	}))
+	defer server.Close()
+
+	tests := []tuple[string]{
+		{fmt.Sprintf(`http_obtener("%s")["cuerpo"];`, server.URL), "hola"},
+		{fmt.Sprintf(`http_enviar("%s", "datos")["cuerpo"];`, server.URL), "text/plain; charset=utf-8 datos"},
+		{fmt.Sprintf(`http_enviar("%s", "{}", "application/json")["cuerpo"];`, server.URL), "application/json {}"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateWithNetwork(test.source)
+		e.testStringObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateWithNetwork(fmt.Sprintf(`http_obtener("%s")["estado"];`, server.URL))
+	e.testIntegerObject(evaluated, 200)
+
+	evaluated = e.evaluateWithNetwork(fmt.Sprintf(`http_enviar("%s", "")["estado"];`, server.URL))
+	e.testIntegerObject(evaluated, 201)
+
+	// the network access is disabled by default
+	evaluated = e.evaluateTests(fmt.Sprintf(`http_obtener("%s");`, server.URL))
+	e.testErrorObject(evaluated, "acceso a la red deshabilitado para http_obtener")
+
+	evaluated = e.evaluateWithNetwork(`http_obtener(1);`)
+	e.testErrorObject(evaluated, "argumento para http_obtener no valido, se recibio entero")
+}
+
+func (e *EvaluatorTests) TestArguments() {
+	program := p.NewParser(l.NewLexer(`args := argumentos(); args[1] + texto(largo(args));`)).ParseProgam()
+	env := obj.NewEnviroment(nil)
+	env.SetArgs([]string{"script.aura", "uno"})
+	e.testStringObject(evaluator.Evaluate(program, env), "uno2")
+
+	evaluated := e.evaluateTests(`largo(argumentos());`)
+	e.testIntegerObject(evaluated, 0)
+
+	evaluated = e.evaluateTests(`argumentos(1);`)
+	e.testErrorObject(evaluated, "numero incorrecto de argumentos para argumentos, se recibieron 1, se requieren 0")
+}
+
+func (e *EvaluatorTests) TestRecoverFromPanics() {
+	// a statement without expression can not be created by the parser
+	program := ast.NewProgram([]ast.Stmt{ast.NewExpressionStament(l.NewToken(l.IDENT, "x"), nil)})
+	evaluated := evaluator.Run(program, obj.NewEnviroment(nil))
+	e.testErrorObject(
+		evaluated,
+		"error interno del interprete: Error de evaluacion! Se esperaba una expression pero se obtuvo nulo!",
+	)
+}
+
+func (e *EvaluatorTests) TestErrorPosition() {
+	source := "var x = 1;\nfuncion f(a) {\n\tvar y = 2;\n\tregresa a + \"b\" * 2;\n}\nf(x);"
+	evaluated := e.evaluateTests(source)
+	if !e.IsType(&obj.Error{}, evaluated) {
+		e.T().FailNow()
+	}
+
+	// the error points to the statement inside the function
+	err := evaluated.(*obj.Error)
+	e.Equal(4, err.Line)
+	e.Equal(2, err.Column)
+	e.Equal(
+		"Error: Discrepancia de tipos: texto * entero\n  en linea 4, columna 2\n  \tregresa a + \"b\" * 2;\n  \t^",
+		evaluator.ErrorContext(err, source),
+	)
+
+	evaluated = e.evaluateTests("var x = 1;\n  x / 0;")
+	e.Equal("Error: Division entre 0\n  en linea 2, columna 3\n    x / 0;\n    ^", evaluator.ErrorContext(evaluated.(*obj.Error), "var x = 1;\n  x / 0;"))
+
+	// the errors without position only show the message
+	e.Equal("Error: fallo", evaluator.ErrorContext(&obj.Error{Message: "fallo"}, source))
+
+	// the handled errors are values and dont have position
+	evaluated = e.evaluateTests(`var r = error("fallo"); r;`)
+	e.Equal(0, evaluated.(*obj.Error).Line)
+}
+
+func (e *EvaluatorTests) TestPrintError() {
+	var stderr bytes.Buffer
+	program := p.NewParser(l.NewLexer(`imprimir_error("fallo: ", 1); imprimir_error("otro");`)).ParseProgam()
+	env := obj.NewEnviroment(nil)
+	env.SetStderr(&stderr)
+
+	// the functions write to the stderr of the outer enviroment
+	inner := obj.NewEnviroment(env)
+	e.testNullObject(evaluator.Evaluate(program, inner))
+	e.Equal("fallo: 1\notro\n", stderr.String())
+}
+
+func (e *EvaluatorTests) TestNumberMethods() {
+	tests := []tuple[interface{}]{
+		{"(-5):abs();", 5},
+		{"x := -2.5; x:abs();", 2.5},
+		{"n := -3; n:signo();", -1},
+		{"0:signo();", 0},
+		{"2.5:signo();", 1},
+		{"signo(-0.5);", -1},
+		{"n := 15; n:limitar(0, 10);", 10},
+		{"(-1):limitar(0, 10);", 0},
+		{"5:limitar(0, 10);", 5},
+		{"5:limitar(0, 2.5);", 2.5},
+		{"limitar(0.5, 1, 2);", 1.0},
+		{"limitar(7, 1, 2);", 2},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case float64:
+			e.testFloatObject(evaluated, expected)
+		}
+	}
+
+	errors := []tuple[string]{
+		{`5:limitar("a", 2);`, "argumento para limitar no valido, se recibio texto"},
+		{"5:limitar(1);", "numero incorrecto de argumentos para limitar, se recibieron 1, se requieren 3"},
+		{"5:limitar(3, 1);", "el minimo no puede ser mayor al maximo en limitar: 3 > 1"},
+		{`signo("a");`, "argumento para signo no valido, se recibio texto"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestStringPrefixAndSuffix() {
+	tests := []tuple[bool]{
+		{`"archivo.txt":termina_con(".txt");`, true},
+		{`"archivo.txt":termina_con(".aura");`, false},
+		{`"prefijo_x":empieza_con("prefijo");`, true},
+		{`"prefijo_x":empieza_con("x");`, false},
+		{`s := "abc"; s:empieza_con("");`, true},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests(`"abc":empieza_con(1);`)
+	e.testErrorObject(evaluated, "argumento para empieza_con no valido, se recibio entero")
+}
+
+func (e *EvaluatorTests) TestStringPad() {
+	tests := []tuple[string]{
+		{`"5":rellenar_izquierda(3, "0");`, "005"},
+		{`"5":rellenar_derecha(3, "0");`, "500"},
+		{`"ab":rellenar_izquierda(4);`, "  ab"},
+		{`"ab":rellenar_derecha(7, "xy");`, "abxyxyx"},
+		{`"hola":rellenar_izquierda(2, "0");`, "hola"},
+		{`"ñu":rellenar_izquierda(4, "·");`, "··ñu"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testStringObject(evaluated, test.expected)
+	}
+
+	errors := []tuple[string]{
+		{`"5":rellenar_izquierda("3");`, "argumento para rellenar_izquierda no valido, se recibio texto"},
+		{`"5":rellenar_derecha(3, "");`, "el relleno de rellenar_derecha no puede estar vacio"},
+		{`"5":rellenar_derecha();`, "numero incorrecto de argumentos para rellenar_derecha, se recibieron 0, se requieren 2"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestSubstring() {
+	tests := []tuple[string]{
+		{`"hola mundo":subcadena(0, 4);`, "hola"},
+		{`"hola mundo":subcadena(5);`, "mundo"},
+		{`"hola mundo":subcadena(-5);`, "mundo"},
+		{`"hola mundo":subcadena(0, -6);`, "hola"},
+		{`"hola":subcadena(2, 100);`, "la"},
+		{`"hola":subcadena(3, 1);`, ""},
+		{`"añoño":subcadena(1, 3);`, "ño"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testStringObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests(`"hola":subcadena("a");`)
+	e.testErrorObject(evaluated, "argumento para subcadena no valido, se recibio texto")
+
+	evaluated = e.evaluateTests(`"hola":subcadena();`)
+	e.testErrorObject(evaluated, "numero incorrecto de argumentos para subcadena, se recibieron 0, se requieren 2")
+}
+
+func (e *EvaluatorTests) TestCharacterCodes() {
+	tests := []tuple[interface{}]{
+		{`codigo("A");`, 65},
+		{`codigo("ñ");`, 241},
+		{`caracter(65);`, "A"},
+		{`caracter(128512);`, "😀"},
+		{`caracter(codigo("a") + 1);`, "b"},
+		{`codigo("ab");`, `codigo requiere un solo caracter, se recibio "ab"`},
+		{`codigo("");`, `codigo requiere un solo caracter, se recibio ""`},
+		{`codigo(1);`, "argumento para codigo no valido, se recibio entero"},
+		{`caracter(-1);`, "-1 no es un codigo de caracter valido"},
+		{`caracter(55296);`, "55296 no es un codigo de caracter valido"},
+		{`caracter("a");`, "argumento para caracter no valido, se recibio texto"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case string:
+			if err, isErr := evaluated.(*obj.Error); isErr {
+				e.Equal(expected, err.Message)
+			} else {
+				e.testStringObject(evaluated, expected)
+			}
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestListSlices() {
+	tests := []tuple[[]int]{
+		{"lista[1, 2, 3, 4, 5]:tomar(3);", []int{1, 2, 3}},
+		{"lista[1, 2]:tomar(5);", []int{1, 2}},
+		{"lista[1, 2]:tomar(-1);", []int{}},
+		{"lista[1, 2, 3, 4, 5]:saltar(2);", []int{3, 4, 5}},
+		{"lista[1, 2]:saltar(5);", []int{}},
+		{"lista[1, 2]:saltar(-1);", []int{1, 2}},
+		{"lista[1, 2, 3, 4, 5]:sublista(1, 4);", []int{2, 3, 4}},
+		{"lista[1, 2, 3, 4, 5]:sublista(-2, 10);", []int{4, 5}},
+		{"lista[1, 2, 3]:sublista(2, 1);", []int{}},
+		{"l := lista[1, 2, 3]; x := l:tomar(2); x:agregar(9); l;", []int{1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntArrayObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests(`lista[1]:tomar("a");`)
+	e.testErrorObject(evaluated, "argumento para tomar no valido, se recibio texto")
+
+	evaluated = e.evaluateTests(`lista[1]:sublista(1);`)
+	e.testErrorObject(evaluated, "numero incorrecto de argumentos para sublista, se recibieron 1, se requieren 2")
+}
+
+func (e *EvaluatorTests) TestListChunk() {
+	tests := []tuple[string]{
+		{"lista[1, 2, 3, 4, 5]:agrupar(2);", "[[1, 2], [3, 4], [5]]"},
+		{"lista[1, 2, 3]:agrupar(3);", "[[1, 2, 3]]"},
+		{"lista[1, 2]:agrupar(5);", "[[1, 2]]"},
+		{"lista[]:agrupar(2);", "[]"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.IsType(&obj.List{}, evaluated)
+		e.Equal(test.expected, evaluated.Inspect())
+	}
+
+	evaluated := e.evaluateTests("lista[1]:agrupar(0);")
+	e.testErrorObject(evaluated, "el tamaño de agrupar debe ser positivo, se recibio 0")
+
+	evaluated = e.evaluateTests(`lista[1]:agrupar("2");`)
+	e.testErrorObject(evaluated, "argumento para agrupar no valido, se recibio texto")
+}
+
+func (e *EvaluatorTests) TestListBuiltin() {
+	tests := []tuple[string]{
+		{"lista(rango(5));", "[0, 1, 2, 3, 4]"},
+		{"lista();", "[]"},
+		{`lista("hola");`, "[h, o, l, a]"},
+		{`lista(mapa{"a" => 1, "b" => 2});`, "[a, b]"},
+		{"l := lista[1, 2]; c := lista(l); c:agregar(3); l;", "[1, 2]"},
+		{"largo(lista(rango(3)));", "3"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect())
+	}
+
+	evaluated := e.evaluateTests("lista(1);")
+	e.testErrorObject(evaluated, "argumento para lista no valido, se recibio entero")
+}
+
+func (e *EvaluatorTests) TestMapBuiltin() {
+	tests := []tuple[string]{
+		{`mapa(lista[lista["a", 1], lista["b", 2]]);`, "{a => 1, b => 2}"},
+		{"mapa();", "{}"},
+		{"mapa(lista[]);", "{}"},
+		{`m := mapa(lista[lista[1, "uno"]]); m[1];`, "uno"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect())
+	}
+
+	errors := []tuple[string]{
+		{`mapa(lista[lista["a", 1], lista["a", 2]]);`, "la llave ya existe en el mapa"},
+		{`mapa(lista[lista["a"]]);`, "mapa requiere pares de llave y valor, se recibio [a]"},
+		{`mapa(lista[1]);`, "mapa requiere pares de llave y valor, se recibio 1"},
+		{`mapa("a");`, "argumento para mapa no valido, se recibio texto"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestProgramValue() {
+	tests := []tuple[interface{}]{
+		{"1; 2; 3;", 3},
+		{"x := 5;", 5},
+		{"x := 1; x + 1;", 2},
+		{"regresa 4; 5;", 4},
+		{"1; var y = 2;", nil},
+		{"1; funcion f() { regresa 1; }", nil},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		if expected, isInt := test.expected.(int); isInt {
+			e.testIntegerObject(evaluated, expected)
+		} else {
+			e.testNullObject(evaluated)
+		}
+	}
+
+	// all the statements are executed even if only the last value is returned
+	evaluated := e.evaluateTests("l := lista[]; l:agregar(1); l:agregar(2); largo(l);")
+	e.testIntegerObject(evaluated, 2)
+}
+
+func (e *EvaluatorTests) TestLoopControlOutsideLoop() {
+	tests := []tuple[string]{
+		{"romper;", "romper fuera de un bucle"},
+		{"continuar;", "continuar fuera de un bucle"},
+		{"1; si (verdadero) { romper; } 2;", "romper fuera de un bucle"},
+		{"funcion f() { continuar; } f();", "continuar fuera de un bucle"},
+		{"funcion f() { romper; } por (i en rango(3)) { f(); }", "romper fuera de un bucle"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests("x := 0; por (i en rango(5)) { si (i == 3) { romper; } x += i; } x;")
+	e.testIntegerObject(evaluated, 3)
+}
+
+func (e *EvaluatorTests) TestTopLevelReturn() {
+	// a return at top level halts the program with its value
+	tests := []tuple[int]{
+		{"regresa 5; 6;", 5},
+		{"si (verdadero) { regresa 5; } 6;", 5},
+		{"por (i en rango(3)) { si (i == 1) { regresa i; } } 9;", 1},
+		{"x := 0; mientras (verdadero) { x += 1; si (x == 4) { regresa x * 2; } } 0;", 8},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntegerObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestEmptyProgram() {
+	for _, source := range []string{"", "   ", "// just a comment"} {
+		evaluated := e.evaluateTests(source)
+		e.testNullObject(evaluated)
+	}
+}
+
+func (e *EvaluatorTests) TestBuiltinsShadowingAndListing() {
+	tests := []tuple[interface{}]{
+		{"var escribir = funcion(x) { regresa x * 2; }; escribir(4);", 8},
+		{"largo := 3; largo + 1;", 4},
+		{"funcion f() { var tipo = 1; regresa tipo; } f(); tipo(1);", "entero"},
+		{`l := ayuda(); l:contiene("escribir");`, true},
+		{`ayuda():contiene("http_obtener");`, true},
+		{`ayuda():contiene("no_existe");`, false},
+		{`ayuda()[0] < ayuda()[1];`, true},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case string:
+			e.testStringObject(evaluated, expected)
+		case bool:
+			e.testBooleanObject(evaluated, expected)
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestSyncEnviroment() {
+	shared := obj.NewSyncEnviroment(nil)
+	shared.SetItem("base", &obj.Number{Value: 10})
+
+	// run with -race to check that the shared scope is guarded
+	var wg sync.WaitGroup
+	results := make([]obj.Object, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			source := fmt.Sprintf("x := base + %d; var propio%d = x; base = 10; x;", i, i)
+			program := p.NewParser(l.NewLexer(source)).ParseProgam()
+			results[i] = evaluator.Evaluate(program, obj.NewEnviroment(shared))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		e.testIntegerObject(result, 10+i)
+	}
+}
+
+func (e *EvaluatorTests) TestParallelMap() {
+	tests := []tuple[[]int]{
+		{"lista[1, 2, 3, 4]:mapear_paralelo(|x| => x * x);", []int{1, 4, 9, 16}},
+		{"lista(rango(50)):mapear_paralelo(|x| => x + 1, 4):tomar(3);", []int{1, 2, 3}},
+		{"funcion doble(x) { regresa x * 2; } lista[5, 6]:mapear_paralelo(doble, 1);", []int{10, 12}},
+		{"lista[]:mapear_paralelo(|x| => x);", []int{}},
+		// the workers write the same variable, go test -race checks the scopes are locked
+		{"var n = 0; lista(rango(16)):mapear_paralelo(|x| => { n = x; dormir(1); var y = n; x }, 8):tomar(2);", []int{0, 1}},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntArrayObject(evaluated, test.expected)
+	}
+
+	errors := []tuple[string]{
+		{"lista[1, 0, 2]:mapear_paralelo(|x| => 10 / x);", "Division entre 0"},
+		{"lista[1]:mapear_paralelo(|x| => x, 0);", "mapear_paralelo requiere al menos un trabajador, se recibio 0"},
+		{"lista[1]:mapear_paralelo(1);", "se requiere una funcion para mapear_paralelo"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestChannels() {
+	tests := []tuple[interface{}]{
+		{"c := canal(); girar(|x| => enviar(c, x * 2), 21); recibir(c);", 42},
+		{"c := canal(1); enviar(c, 5); recibir(c);", 5},
+		{`
+			c := canal();
+			funcion productor(n) {
+				por (i en rango(n)) {
+					enviar(c, i);
+				}
+			}
+			girar(productor, 4);
+			total := 0;
+			por (i en rango(4)) {
+				total += recibir(c);
+			}
+			total;
+		`, 6},
+		{"tipo(canal());", "canal"},
+		{"c := canal(2); enviar(c, 1); enviar(c, 2); recibir(c) + recibir(c);", 3},
+		{`
+			ping := canal();
+			pong := canal();
+			girar(funcion() {
+				por (i en rango(3)) {
+					enviar(pong, recibir(ping) + 1);
+				}
+			});
+			n := 0;
+			por (i en rango(3)) {
+				enviar(ping, n);
+				n = recibir(pong);
+			}
+			n;
+		`, 3},
+		{"c := canal(); girar(|x| => enviar(c, x), 5); dormir(10); recibir(c);", 5},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case string:
+			e.testStringObject(evaluated, expected)
+		}
+	}
+
+	errors := []tuple[string]{
+		{"canal(-1);", "el tamaño del canal no puede ser negativo: -1"},
+		{"enviar(1, 2);", "argumento para enviar no valido, se recibio entero"},
+		{"girar(1);", "1 no es una funcion"},
+		{"c := canal(); recibir(c);", "recibir esperaria para siempre, todas las funciones estan esperando un canal o un futuro"},
+		{"c := canal(); enviar(c, 1);", "enviar esperaria para siempre, todas las funciones estan esperando un canal o un futuro"},
+		{"c := canal(1); enviar(c, 1); enviar(c, 2);", "enviar esperaria para siempre, todas las funciones estan esperando un canal o un futuro"},
+		{"c := canal(); girar(funcion() { 1 }); recibir(c);", "recibir esperaria para siempre, todas las funciones estan esperando un canal o un futuro"},
+		{"c := canal(); f := girar(|x| => recibir(x), c); esperar(f);", "esperar esperaria para siempre, todas las funciones estan esperando un canal o un futuro"},
+		{"girar();", "numero incorrecto de argumentos para girar, se recibieron 0, se requieren 1"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestChannelsPerInterpreter() {
+	// a deadlock in one program does not stop the functions of other program
+	// running at the same time in the same host
+	sources := []string{
+		"c := canal(); recibir(c);",
+		`
+			c := canal();
+			f := girar(|x| => recibir(x) * 2, c);
+			dormir(100);
+			enviar(c, 21);
+			esperar(f);
+		`,
+	}
+
+	results := make([]obj.Object, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source string) {
+			defer wg.Done()
+			results[i] = e.evaluateTests(source)
+		}(i, source)
+	}
+	wg.Wait()
+
+	e.testErrorObject(results[0], "recibir esperaria para siempre, todas las funciones estan esperando un canal o un futuro")
+	e.testIntegerObject(results[1], 42)
+}
+
+func (e *EvaluatorTests) TestFutures() {
+	tests := []tuple[interface{}]{
+		{"f := girar(|x| => x * 2, 21); esperar(f);", 42},
+		{"f := girar(|a, b| => a + b, 1, 2); f:esperar();", 3},
+		{`
+			funcion cuadrado(n) {
+				regresa n * n;
+			}
+			futuros := lista[girar(cuadrado, 2), girar(cuadrado, 3), girar(cuadrado, 4)];
+			total := 0;
+			por (f en futuros) {
+				total += esperar(f);
+			}
+			total;
+		`, 29},
+		{"tipo(girar(|x| => x, 1));", "futuro"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case string:
+			e.testStringObject(evaluated, expected)
+		}
+	}
+
+	errors := []tuple[string]{
+		{"f := girar(|x| => x / 0, 1); esperar(f);", "Division entre 0"},
+		{"esperar(1);", "argumento para esperar no valido, se recibio entero"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestMatch() {
+	source := `
+		funcion describir(v) {
+			regresa coincidir v {
+				0 => "cero";
+				-1 => "menos uno";
+				[] => "vacia";
+				[a] => "uno " + texto(a);
+				[a, [b, _]] => "anidada " + texto(a + b);
+				[a, b] => "par " + texto(a + b);
+				mapa{"nombre" => n, "edad" => 30} => "persona " + n;
+				nulo => "nada";
+				_ => "otro";
+			};
+		}
+	`
+	tests := []tuple[interface{}]{
+		{"describir(0);", "cero"},
+		{"describir(-1);", "menos uno"},
+		{"describir(0.0);", "cero"},
+		{"describir(lista[]);", "vacia"},
+		{"describir(lista[5]);", "uno 5"},
+		{"describir(lista[1, lista[2, 3]]);", "anidada 3"},
+		{"describir(lista[1, 2]);", "par 3"},
+		{`describir(mapa{"nombre" => "ana", "edad" => 30, "pais" => "mx"});`, "persona ana"},
+		{`describir(mapa{"nombre" => "ana", "edad" => 31});`, "otro"},
+		{"describir(nulo);", "nada"},
+		{"describir(lista[1, 2, 3]);", "otro"},
+		{`coincidir "hola" { "hola" => { x := 1; x + 1 } };`, 2},
+		{"a := 10; coincidir lista[1] { [a] => a }; a;", 10},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(source + test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case string:
+			e.testStringObject(evaluated, expected)
+		}
+	}
+
+	evaluated := e.evaluateTests("coincidir 5 { 1 => 2 };")
+	e.testErrorObject(evaluated, "ningun patron coincide con 5")
+}
+
+func (e *EvaluatorTests) TestMatchGuards() {
+	source := `
+		funcion clasificar(v) {
+			regresa coincidir v {
+				0 => "cero";
+				[a, b] si (a == b) => "iguales";
+				[a, b] => "distintos";
+				x si (x < 0) => "negativo";
+				x si (x > 10) => "grande";
+				_ => "pequeño";
+			};
+		}
+	`
+	tests := []tuple[string]{
+		{"clasificar(0);", "cero"},
+		{"clasificar(-3);", "negativo"},
+		{"clasificar(20);", "grande"},
+		{"clasificar(5);", "pequeño"},
+		{"clasificar(lista[1, 1]);", "iguales"},
+		{"clasificar(lista[1, 2]);", "distintos"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(source + test.source)
+		e.testStringObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests("coincidir 5 { x si (x > y) => x };")
+	e.testErrorObject(evaluated, "Identificador no encontrado: y")
+}
+
+func (e *EvaluatorTests) TestBuiltinFunctions() {
+	tests := []tuple[interface{}]{
+		{source: `largo("");`, expected: 0},
+		{source: `largo("cuatro");`, expected: 6},
+		{source: `largo("hola mundo");`, expected: 10},
+		{source: "largo(1);", expected: "argumento para largo no valido, se recibio entero"},
+		{
+			source:   `largo("uno", "dos");`,
+			expected: "numero incorrecto de argumentos para largo, se recibieron 2, se requieren 1",
+		},
+		{source: "tipo(1);", expected: "entero"},
+		{source: "tipo(1.5);", expected: "flotante"},
+		{source: "tipo(verdadero)", expected: "booleano"},
+		{source: `tipo("hello world")`, expected: "texto"},
+		{source: `entero("1")`, expected: 1},
+		{source: `entero("hola")`, expected: "No se puede parsear como entero hola"},
+		{source: "texto(12)", expected: "12"},
+		{source: "a := lista[2,3,4]; largo(a);", expected: 3},
+		{source: "a := lista[2,3,4]; tipo(a);", expected: "lista"},
+		{source: `b := mapa{"a" => 2}; largo(b);`, expected: 1},
+		{source: `b := mapa{"a" => 2}; tipo(b);`, expected: "mapa"},
+		{source: `b := mapa{"a" => 2}; tipo(b["a"]);`, expected: "entero"},
+		{source: `a := "aura"; a:contiene("a");`, expected: true},
+		{source: `a := "aura"; a:contiene("au");`, expected: true},
+		{source: `a := "aura"; a:contiene("ra");`, expected: true},
+		{source: `a := "aura"; a:contiene("ara");`, expected: false},
+		{source: `a := "aura"; a:contiene("b");`, expected: false},
+		{source: `a := lista[2,34,45]; a:contiene(2);`, expected: true},
+		{source: `a := lista[2,34,45]; a:contiene(342);`, expected: false},
+		{source: `formatear("hola soy {}", "dario");`, expected: "hola soy dario"},
+		{source: `formatear("hi im {}, i am {} years old", "dario", 19);`, expected: "hi im dario, i am 19 years old"},
+		{source: `abs(-1)`, expected: 1},
+		{source: "abs(1)", expected: 1},
+		{source: "abs(-2.5)", expected: 2.5},
+		{source: "abs(2.5)", expected: 2.5},
+		{source: `abs("h")`, expected: "argumento para abs no valido, se recibio texto"},
+		{source: `flotante(5)`, expected: 5.0},
+		{source: `flotante(10)`, expected: 10.0},
+		{source: `flotante("10.5")`, expected: 10.5},
+		{source: `flotante("5.5")`, expected: 5.5},
+		{source: `flotante("h")`, expected: "no se pudo parsear como flotante h"},
+		{source: "suma(lista[2,3,4])", expected: 9},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch val := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, val)
+
+		case string:
+			if str, isStr := evaluated.(*obj.String); isStr {
+				e.testStringObject(str, val)
+			} else {
+				e.testErrorObject(evaluated, val)
+			}
+
+		case bool:
+			e.testBooleanObject(evaluated, val)
+
+		case float64:
+			e.testFloatObject(evaluated, val)
+
+		default:
+			panic("Some weird shit happend!")
+		}
 	}
 }
 
@@ -680,6 +2142,19 @@ func (e *EvaluatorTests) TestBreakAndContinue() {
 		evaluated := e.evaluateTests(test.source)
 		e.testIntArrayObject(evaluated, test.expected)
 	}
+
+	// the other loops stop or skip to the next iteration in the same way
+	totals := []tuple[int]{
+		{"n := 0; suma := 0; mientras (n < 10) { n++; si (n == 3) { continuar; } si (n == 6) { romper; } suma += n; } suma;", 12},
+		{"suma := 0; por (var i = 0; i < 10; i++) { si (i % 2 == 0) { continuar; } si (i > 7) { romper; } suma += i; } suma;", 16},
+		{"pasos := 0; por (var i = 0; i < 5; i++) { pasos += 1; continuar; } pasos;", 5},
+		{"suma := 0; por (x en rango(3)) { por (y en rango(3)) { si (y == 1) { romper; } suma += 10; } suma += x; } suma;", 33},
+	}
+
+	for _, test := range totals {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntegerObject(evaluated, test.expected)
+	}
 }
 
 func (e *EvaluatorTests) TestImportStatement() {
@@ -702,17 +2177,58 @@ func (e *EvaluatorTests) testErrorObject(evlauated obj.Object, expected string)
 	e.Equal(expected, err.Message)
 }
 
-func (e *EvaluatorTests) TestFunctionEvaluation() {
-	source := "funcion(x) { x + 2; };"
-	evaluated := e.evaluateTests(source)
-	if !e.IsType(&obj.Def{}, evaluated) {
-		e.T().FailNow()
+func (e *EvaluatorTests) TestFunctionEvaluation() {
+	source := "funcion(x) { x + 2; };"
+	evaluated := e.evaluateTests(source)
+	if !e.IsType(&obj.Def{}, evaluated) {
+		e.T().FailNow()
+	}
+
+	function := evaluated.(*obj.Def)
+	e.Equal(1, len(function.Parameters))
+	e.Equal("x", function.Parameters[0].Str())
+	e.Equal("(x + 2)", function.Body.Str())
+}
+
+func (e *EvaluatorTests) TestCallDepth() {
+	tests := []tuple[string]{
+		{"f := funcion(n) { f(n + 1) }; f(0);", "se excedio el maximo de 10000 llamadas anidadas"},
+		{"clase C() { f(n) => f(n + 1) } c := nuevo C(); c.f(0);", "se excedio el maximo de 10000 llamadas anidadas"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+
+	// the calls that returned with the error are not counted anymore
+	evaluated := e.evaluateTests(`
+		f := funcion(n) { f(n + 1) };
+		intentar { f(0) } excepto(e) { nulo };
+		funcion cuenta(n) { si (n == 0) { regresa 0 } regresa 1 + cuenta(n - 1) }
+		cuenta(5000);
+	`)
+	e.testIntegerObject(evaluated, 5000)
+
+	// each chain of calls has its own depth, the calls running at the same
+	// time are not added together
+	source := `funcion prof(n) { si (n == 0) { regresa 0 } regresa 1 + prof(n - 1) }`
+	lists := []tuple[[]int]{
+		{source + `lista[4000, 4000, 4000, 4000]:mapear_paralelo(|x| => prof(x), 4);`, []int{4000, 4000, 4000, 4000}},
+		{source + `futuros := lista[girar(prof, 9000), girar(prof, 9000)]; lista[prof(9000), esperar(futuros[0]), esperar(futuros[1])];`, []int{9000, 9000, 9000}},
 	}
 
-	function := evaluated.(*obj.Def)
-	e.Equal(1, len(function.Parameters))
-	e.Equal("x", function.Parameters[0].Str())
-	e.Equal("(x + 2)", function.Body.Str())
+	for _, test := range lists {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntArrayObject(evaluated, test.expected)
+	}
+
+	evaluated = e.evaluateTests(source + `prof(9000); lista[1]:map(|x| => prof(9990));`)
+	e.testIntArrayObject(evaluated, []int{9990})
+
+	// the calls made by the methods are counted in the chain of the caller
+	evaluated = e.evaluateTests(source + `lista[1]:map(|x| => prof(9999))[0];`)
+	e.testErrorObject(evaluated, "se excedio el maximo de 10000 llamadas anidadas")
 }
 
 func (e *EvaluatorTests) TestFunctionCalls() {
@@ -844,6 +2360,341 @@ func (e *EvaluatorTests) TestTryExcept() {
 	}
 }
 
+func (e *EvaluatorTests) TestErrorValues() {
+	convert := `
+		funcion convertir(x) {
+			intentar {
+				regresa entero(x);
+			} excepto(e) {
+				regresa e;
+			}
+		}
+	`
+	tests := []tuple[interface{}]{
+		{`es_error(error("fallo"));`, true},
+		{"es_error(5);", false},
+		{`e := error("fallo"); es_error(e);`, true},
+		{`e := error("fallo"); m := mapa{"e" => e}; es_error(m["e"]);`, true},
+		{`e := error("fallo"); l := lista[1, e]; es_error(l[1]);`, true},
+		{`e := error("fallo"); 1; 2;`, 2},
+		{convert + `es_error(convertir("a"));`, true},
+		{convert + `largo(lista[v por (v en lista["1", "a", "b"]) si (es_error(convertir(v)))]);`, 2},
+		{`r := 0; intentar { x := error("valor"); r = 1; } excepto(e) { r = 2; }; r;`, 1},
+		{`error("fallo") == error("fallo");`, true},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case bool:
+			e.testBooleanObject(evaluated, expected)
+		}
+	}
+
+	// an error value can be the result, the failed operations still stop the evaluation
+	errors := []tuple[string]{
+		{`e := error("fallo"); e;`, "fallo"},
+		{"x := 1 / 0; 5;", "Division entre 0"},
+		{`l := lista[1]; l[0] = 1 / 0; 5;`, "Division entre 0"},
+		{`es_error(1 / 0);`, "Division entre 0"},
+		{"error(1);", "argumento para error no valido, se recibio entero"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestTryValue() {
+	tests := []tuple[interface{}]{
+		{`r := intentar(entero("5")); r[0];`, 5},
+		{`r := intentar(entero("5")); r[1];`, nil},
+		{`r := intentar(entero("a")); r[0];`, nil},
+		{`r := intentar(entero("a")); es_error(r[1]);`, true},
+		{`r := intentar(1 / 0); r[1];`, "Division entre 0"},
+		{`r := intentar(error("fallo")); r[1];`, "fallo"},
+		{`largo(intentar(5));`, 2},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case bool:
+			e.testBooleanObject(evaluated, expected)
+		case string:
+			e.testErrorObject(evaluated, expected)
+		default:
+			e.testNullObject(evaluated)
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestSameObject() {
+	tests := []tuple[bool]{
+		{"a := lista[1, 2]; b := lista[1, 2]; mismo(a, b);", false},
+		{"a := lista[1, 2]; b := lista[1, 2]; a == b;", true},
+		{"a := lista[1, 2]; b := a; mismo(a, b);", true},
+		{"a := lista[1, 2]; b := a; b:agregar(3); largo(a) == 3 && mismo(a, b);", true},
+		{`a := mapa{"x" => 1}; b := mapa{"x" => 1}; mismo(a, b);`, false},
+		{`a := mapa{"x" => 1}; mismo(a, a);`, true},
+		{"mismo(1, 1);", true},
+		{"mismo(1, 1.0);", false},
+		{`mismo("a", "a");`, true},
+		{"mismo(nulo, nulo);", true},
+		{"f := |x| => x; g := |x| => x; mismo(f, g);", false},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestFreeze() {
+	tests := []tuple[interface{}]{
+		{"l := congelar(lista[1, 2]); esta_congelado(l);", true},
+		{"l := lista[1, 2]; esta_congelado(l);", false},
+		{`m := congelar(mapa{"l" => lista[1]}); esta_congelado(m["l"]);`, true},
+		{"esta_congelado(5);", false},
+		{"l := congelar(lista[1, 2]); l[0] + l[1];", 3},
+		{"l := congelar(lista[1, 2]); largo(l:map(|x| => x * 2));", 2},
+		{"l := congelar(lista[1]); mismo(l, congelar(l));", true},
+		{"l := lista[1]; mismo(l, congelar(l));", false},
+		{"l := lista[1]; congelar(l); esta_congelado(l);", false},
+		{"l := lista[1]; f := congelar(l); l[0] = 5; f[0];", 1},
+		{"l := lista[lista[1]]; f := congelar(l); l[0]:agregar(2); largo(f[0]);", 1},
+		{`m := mapa{"a" => 1}; f := congelar(m); m["a"] += 5; m["a"] + f["a"];`, 7},
+		{"l := congelar(lista[1]); x := l[0]; x += 5; x++; -x; l[0];", 1},
+		{"l := congelar(lista[1]); f := funcion(n) { n += 1 }; f(l[0]); l[0];", 1},
+		{"l := lista[1]; l:agregar(l); f := congelar(l); mismo(f, f[1]);", true},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case bool:
+			e.testBooleanObject(evaluated, expected)
+		}
+	}
+
+	errors := []tuple[string]{
+		{"l := congelar(lista[1, 2]); l[0] = 5;", "no se puede modificar un valor de tipo lista congelado"},
+		{"l := congelar(lista[1, 2]); l:agregar(3);", "no se puede modificar un valor de tipo lista congelado"},
+		{"l := congelar(lista[1, 2]); l:pop();", "no se puede modificar un valor de tipo lista congelado"},
+		{`m := congelar(mapa{"a" => 1}); m["b"] = 2;`, "no se puede modificar un valor de tipo mapa congelado"},
+		{`m := congelar(mapa{"l" => lista[1]}); m["l"][0] = 2;`, "no se puede modificar un valor de tipo lista congelado"},
+		{"l := congelar(lista[1]); l[0] += 5;", "no se puede modificar un valor de tipo lista congelado"},
+		{"l := congelar(lista[1]); l[0]++;", "no se puede modificar un valor de tipo lista congelado"},
+		{`m := congelar(mapa{"a" => 1}); m["a"] += 5;`, "no se puede modificar un valor de tipo mapa congelado"},
+		{`m := congelar(mapa{"l" => lista[1]}); m["l"][0]--;`, "no se puede modificar un valor de tipo lista congelado"},
+		{"congelar(1);", "argumento para congelar no valido, se recibio entero"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestHash() {
+	tests := []tuple[bool]{
+		{"hash(1) == hash(1);", true},
+		{"hash(1) == hash(1.0);", true},
+		{"hash(1) == hash(2);", false},
+		{`hash(1) == hash("1");`, false},
+		{`hash("aura") == hash("aura");`, true},
+		{"hash(verdadero) == hash(verdadero);", true},
+		{`hash(congelar(lista[1, "a"])) == hash(congelar(lista[1, "a"]));`, true},
+		{`hash(congelar(lista[1, "a"])) == hash(congelar(lista["a", 1]));`, false},
+		{`hash(congelar(mapa{"a" => lista[1]})) == hash(congelar(mapa{"a" => lista[1]}));`, true},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+
+	errors := []tuple[string]{
+		{"hash(lista[1]);", "no se puede calcular el hash de una lista sin congelar"},
+		{`hash(mapa{"a" => 1});`, "no se puede calcular el hash de un mapa sin congelar"},
+		{"hash(congelar(lista[|x| => x]));", "no se puede calcular el hash de un valor de tipo funcion"},
+		{"hash();", "numero incorrecto de argumentos para hash, se recibieron 0, se requieren 1"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestObjectTypes() {
+	tests := []tuple[obj.ObjectType]{
+		{"1;", obj.INTEGERS},
+		{"1.5;", obj.FLOATING},
+		{`"aura";`, obj.STRINGTYPE},
+		{"verdadero;", obj.BOOLEAN},
+		{"nulo;", obj.NULL},
+		{"lista[1];", obj.LIST},
+		{`mapa{"a" => 1};`, obj.DICT},
+		{"conjunto();", obj.SET},
+		{"|x| => x;", obj.DEF},
+		{"largo;", obj.BUILTIN},
+		{"ahora();", obj.TIME},
+		{"canal();", obj.CHANNEL},
+		{"girar(|x| => x, 1);", obj.FUTURE},
+		{`error("e");`, obj.ERROR},
+		{"clase A(x) { f() { regresa x; } } nuevo A(1);", obj.CLASS},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Type(), test.source)
+	}
+
+	for objType, name := range obj.Types {
+		if objType != obj.ObjTypeHead {
+			e.NotEmpty(name, "tipo sin nombre: %d", objType)
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestInspect() {
+	tests := []tuple[string]{
+		{"inspeccionar(1);", "1(entero)"},
+		{`inspeccionar("a");`, `"a"(texto)`},
+		{"inspeccionar(lista[]);", "lista(0)[]"},
+		{"inspeccionar(lista[1, 2.5]);", "lista(2)[\n  1(entero),\n  2.5(flotante)\n]"},
+		{
+			`inspeccionar(mapa{"a" => lista[nulo]});`,
+			"mapa(1){\n  \"a\"(texto) => lista(1)[\n    nulo(nulo)\n  ]\n}",
+		},
+		{"inspeccionar(1, 2);", "Error: numero incorrecto de argumentos para inspeccionar, se recibieron 2, se requieren 1"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect())
+	}
+}
+
+func (e *EvaluatorTests) TestTraceHook() {
+	program := p.NewParser(l.NewLexer("a := 1; si (a == 1) { a = 2; }; a;")).ParseProgam()
+	env := obj.NewEnviroment(nil)
+
+	var traced []string
+	env.SetTrace(func(node ast.ASTNode, env *obj.Enviroment) {
+		traced = append(traced, node.Str())
+	})
+
+	evaluated := evaluator.Evaluate(program, env)
+	e.testIntegerObject(evaluated, 2)
+	e.Equal(4, len(traced))
+	e.Equal(program.Staments[0].Str(), traced[0])
+	e.Equal("a = 2", traced[2])
+}
+
+func (e *EvaluatorTests) TestEnviromentSnapshot() {
+	env := obj.NewEnviroment(nil)
+	run := func(source string) obj.Object {
+		return evaluator.Evaluate(p.NewParser(l.NewLexer(source)).ParseProgam(), env)
+	}
+
+	run(`x := 1; l := lista[1];`)
+	snapshot := env.Snapshot()
+
+	run(`x = 2; y := 3; l:agregar(2);`)
+	e.testIntegerObject(run("x;"), 2)
+
+	e.Nil(env.Restore(snapshot))
+	e.testIntegerObject(run("x;"), 1)
+	e.IsType(&obj.Error{}, run("y;"))
+
+	// the copy is shallow, the values of the lists are not reverted
+	e.testIntArrayObject(run("l;"), []int{1, 2})
+
+	// the same snapshot can be restored again
+	run(`x = 5;`)
+	e.Nil(env.Restore(snapshot))
+	e.testIntegerObject(run("x;"), 1)
+
+	e.NotNil(obj.NewEnviroment(nil).Restore(snapshot))
+	e.NotNil(env.Restore(nil))
+}
+
+func (e *EvaluatorTests) TestProfile() {
+	program := p.NewParser(l.NewLexer(`por (i en rango(3)) { i; }; perfil()["Identifier"];`)).ParseProgam()
+	env := obj.NewEnviroment(nil)
+	env.EnableProfile()
+
+	evaluated := evaluator.Evaluate(program, env)
+	// three times i, rango and perfil
+	e.testIntegerObject(evaluated, 5)
+	e.Equal(1, env.Profile().Counts()["For"])
+
+	evaluated = e.evaluateTests("perfil();")
+	e.testErrorObject(evaluated, "perfilado deshabilitado para perfil")
+}
+
+func (e *EvaluatorTests) TestMemoryStats() {
+	for _, key := range []string{"asignada", "total_asignada", "sistema", "objetos", "recolecciones", "gorutinas"} {
+		evaluated := e.evaluateTests(fmt.Sprintf(`tipo(memoria()["%s"]);`, key))
+		e.testStringObject(evaluated, "entero")
+	}
+
+	evaluated := e.evaluateTests("memoria(1);")
+	e.testErrorObject(evaluated, "numero incorrecto de argumentos para memoria, se recibieron 1, se requieren 0")
+}
+
+func (e *EvaluatorTests) TestSets() {
+	tests := []tuple[string]{
+		{"conjunto(lista[1, 2, 2, 3]);", "conjunto{1, 2, 3}"},
+		{`conjunto("aba");`, "conjunto{a, b}"},
+		{"conjunto();", "conjunto{}"},
+		{"s := conjunto(); s:agregar(1); s:agregar(1); s;", "conjunto{1}"},
+		{"s := conjunto(lista[1, 2]); s:quitar(1); s;", "conjunto{2}"},
+		{"conjunto(lista[1, 2]):union(conjunto(lista[2, 3]));", "conjunto{1, 2, 3}"},
+		{"conjunto(lista[1, 2]):interseccion(conjunto(lista[2, 3]));", "conjunto{2}"},
+		{"conjunto(lista[1, 2]):diferencia(conjunto(lista[2, 3]));", "conjunto{1}"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect())
+	}
+
+	booleans := []tuple[bool]{
+		{"conjunto(lista[1, 2]):contiene(2);", true},
+		{"conjunto(lista[1, 2]):contiene(3);", false},
+		{"conjunto(lista[1]):quitar(1);", true},
+		{"conjunto(lista[1]):quitar(2);", false},
+	}
+
+	for _, test := range booleans {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+
+	e.testIntegerObject(e.evaluateTests("largo(conjunto(lista[1, 1, 2]));"), 2)
+	e.testIntegerObject(e.evaluateTests("t := 0; por (x en conjunto(lista[1, 2])) { t += x }; t;"), 3)
+
+	errors := []tuple[string]{
+		{"conjunto(1);", "argumento para conjunto no valido, se recibio entero"},
+		{"conjunto(lista[1]):union(lista[1]);", "argumento para union no valido, se recibio lista"},
+	}
+
+	for _, test := range errors {
+		evaluated := e.evaluateTests(test.source)
+		e.testErrorObject(evaluated, test.expected)
+	}
+}
+
 func (e *EvaluatorTests) TestStringEvaluation() {
 	tests := []tuple[string]{
 		{source: `"hello world!"`, expected: "hello world!"},
@@ -857,6 +2708,8 @@ func (e *EvaluatorTests) TestStringEvaluation() {
 			expected: "Goooal!",
 		},
 		{source: `a := ""; por(i en "Hello aura") { a += i }; a;`, expected: "Hello aura"},
+		{source: `a := ""; por(i en "año") { a += i }; a;`, expected: "año"},
+		{source: `a := ""; m := mapa{"x" => 1, "y" => 2}; por(k en m) { a += k }; a;`, expected: "xy"},
 	}
 
 	for _, test := range tests {
@@ -875,6 +2728,7 @@ func (e *EvaluatorTests) TestTernaryIf() {
 		{`x := 5; (x == 5 && x > 4) ? 5 : 4;`, 5},
 		{`x := 5; (x == 10) ? 5 : 4;`, 4},
 		{`x := 5; (x % 2 == 0) ? 5 : 4;`, 4},
+		{`a := 1; b := 2; verdadero ? a : b;`, 1},
 	}
 
 	for _, test := range tests {
@@ -916,6 +2770,245 @@ func (e *EvaluatorTests) testStringObject(evaluated obj.Object, expected string)
 	e.Equal(expected, str.Value)
 }
 
+func (e *EvaluatorTests) TestStringInterpolation() {
+	tests := []tuple[string]{
+		{`nombre := "Ana"; edad := 30; "hola ${nombre}, tienes ${edad + 1}";`, "hola Ana, tienes 31"},
+		{`s := "abc"; "${s:mayusculas()}!";`, "ABC!"},
+		{`l := lista[1, 2]; "${l} ${l[0]}";`, "[1, 2] 1"},
+		{`"${ "a" + "}" }";`, "a}"},
+		{`"\${nombre}";`, "${nombre}"},
+		{`x := 2; "\${x} ${x}";`, "${x} 2"},
+		{`x := 2; "${x}\n\t${x}";`, "2\n\t2"},
+		{`x := 2; "\"${x}\"";`, `"2"`},
+		{`x := 2; "\\${x}";`, `\2`},
+		{`var e = error("boom"); "msg ${e}";`, "msg Error: boom"},
+		{`x := "b"; "${ "a\"}" + x }";`, `a"}b`},
+		{`"${ mapa{"a" => 2}["a"] }";`, "2"},
+		{`m := mapa{"a" => 1}; "${m}";`, "{a => 1}"},
+		{`"${ "x${1 + 1}y" }";`, "x2y"},
+		{`"${'c'} ${nulo}";`, "c nulo"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testStringObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests(`"hola ${desconocido}";`)
+	e.testErrorObject(evaluated, "Identificador no encontrado: desconocido")
+}
+
+func (e *EvaluatorTests) TestNumericEquality() {
+	tests := []tuple[bool]{
+		{"5 == 5.0", true},
+		{"5.0 == 5", true},
+		{"5 != 5.0", false},
+		{"5 == 5.5", false},
+		{"2.5 == 2.5", true},
+		{"2.5 != 2.5", false},
+		{"0.1 + 0.2 == 0.3", false},
+		{`5 == "5"`, false},
+		{`5 != "5"`, true},
+		{`5.0 == "5.0"`, false},
+		{"1 == verdadero", false},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestComparisonAcrossTypes() {
+	declarations := `clase A() {} clase B() {} var f = |x| => x; var g = |x| => x; var i = nuevo A();`
+	values := []string{
+		"1", "1.5", `"a"`, "verdadero", "nulo", `escribir("")`, "lista[1]", "mapa{1 => 2}",
+		"conjunto(lista[1])", "f", "g", "largo", "A", "B", "i", "error(1)",
+	}
+
+	for _, left := range values {
+		for _, right := range values {
+			for _, operator := range []string{"==", "!=", "<", ">="} {
+				// a panic fails the test because the source is not evaluated with Run
+				source := fmt.Sprintf("%s (%s) %s (%s);", declarations, left, operator, right)
+				evaluated := e.evaluateTests(source)
+				if _, isErr := evaluated.(*obj.Error); !isErr {
+					e.IsType(&obj.Bool{}, evaluated, source)
+				}
+			}
+		}
+	}
+
+	tests := []tuple[bool]{
+		{"var f = |x| => x; f == f;", true},
+		{"var f = |x| => x; var g = |x| => x; f == g;", false},
+		{"var f = |x| => x; var g = |x| => x; f != g;", true},
+		{"largo == largo;", true},
+		{"largo == escribir;", false},
+		{"clase A() {} A == A;", true},
+		{"clase A() {} clase B() {} A == B;", false},
+		{"nulo == nulo;", true},
+		{`nulo == escribir("");`, true},
+		{`escribir("") != nulo;`, false},
+		{"nulo == 0;", false},
+		{`nulo == "";`, false},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+
+	evaluated := e.evaluateTests("var f = |x| => x; f < f;")
+	e.testErrorObject(evaluated, "Operador desconocido: funcion < funcion")
+
+	evaluated = e.evaluateTests("nulo < 1;")
+	e.testErrorObject(evaluated, "Discrepancia de tipos: nulo < entero")
+}
+
+func (e *EvaluatorTests) TestTrailingCommas() {
+	tests := []tuple[interface{}]{
+		{"var suma = funcion(a, b,) { regresa a + b; }; suma(1, 2,);", 3},
+		{"var resta = funcion(\n\ta,\n\tb,\n) { regresa a - b; };\nresta(\n\t5,\n\t2,\n);", 3},
+		{"var doble = |x,| => x * 2; doble(4,);", 8},
+		{"largo(lista[1, 2, 3,]);", 3},
+		{"var suma = funcion(a, b) { regresa a + b; }; suma(1,);", "se esperaban 2 argumentos pero se recibieron 1"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		if expected, isInt := test.expected.(int); isInt {
+			e.testIntegerObject(evaluated, expected)
+		} else {
+			e.testErrorObject(evaluated, test.expected.(string))
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestNamedArguments() {
+	function := "var resta = funcion(a, b) { regresa a - b; };"
+	tests := []tuple[interface{}]{
+		{function + "resta(b = 2, a = 5);", 3},
+		{function + "resta(5, b = 2);", 3},
+		{function + "resta(a = 5, b = 2,);", 3},
+		{"var f = |a, b, c| => a * 100 + b * 10 + c; f(1, c = 3, b = 2);", 123},
+		{"clase Calc() { resta(a, b) { regresa a - b; } } c := nuevo Calc(); c.resta(b = 1, a = 4);", 3},
+		{function + "resta(5, c = 2);", "la funcion no tiene un parametro llamado c"},
+		{function + "resta(a = 5, a = 2);", "el argumento a se recibio mas de una vez"},
+		{function + "resta(5, a = 2);", "el argumento a se recibio mas de una vez"},
+		{function + "resta(b = 2);", "falta el argumento a"},
+		{function + "resta(1, 2, 3, b = 2);", "se esperaban 2 argumentos pero se recibieron 4"},
+		{function + "resta(a = 1, b = x);", "Identificador no encontrado: x"},
+		{"largo(valor = lista[1]);", "largo no acepta argumentos con nombre"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		if expected, isInt := test.expected.(int); isInt {
+			e.testIntegerObject(evaluated, expected)
+		} else {
+			e.testErrorObject(evaluated, test.expected.(string))
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestIfAsExpression() {
+	tests := []tuple[interface{}]{
+		{`x := 5; var estado = si (x > 0) { "positivo" } si_no { "no positivo" }; estado;`, "positivo"},
+		{`x := -1; var estado = si (x > 0) { "positivo" } si_no { "no positivo" }; estado;`, "no positivo"},
+		{"x := 5; var doble = si (x > 0) { var y = 2; y * x; } si_no { 0 }; doble;", 10},
+		{"var total = si (verdadero) { 7 } si_no { 8 } + 1; total;", 8},
+		{"var signo = funcion(n) { si (n < 0) { -1 } si_no { 1 } }; signo(-3);", -1},
+		{"var vacio = si (falso) { 1 }; vacio;", nil},
+		{"var vacio = si (verdadero) {}; vacio;", nil},
+		{"var vacio = si (falso) { 1 } si_no { var z = 3; }; vacio;", nil},
+		{"var f = funcion() {}; f();", nil},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		switch expected := test.expected.(type) {
+		case int:
+			e.testIntegerObject(evaluated, expected)
+		case string:
+			e.testStringObject(evaluated, expected)
+		default:
+			e.testNullObject(evaluated)
+		}
+	}
+}
+
+func (e *EvaluatorTests) TestConstants() {
+	tests := []tuple[interface{}]{
+		{"constante PI = 3; PI;", 3},
+		{"constante PI = 3; var area = funcion(r) { regresa PI * r * r; }; area(2);", 12},
+		{"constante DOS = 2; total := 0; por (x en rango(3)) { total += DOS; } total;", 6},
+		{"constante X = 1; var f = funcion(X) { X = X + 1; regresa X; }; f(5);", 6},
+		{"constante PI = 3; PI = 4;", "no se puede reasignar una constante: PI"},
+		{"constante PI = 3; PI := 4;", "no se puede reasignar una constante: PI"},
+		{"constante PI = 3; var PI = 4;", "no se puede reasignar una constante: PI"},
+		{"constante PI = 3; constante PI = 4;", "no se puede reasignar una constante: PI"},
+		{"constante PI = 3; PI += 1;", "no se puede reasignar una constante: PI"},
+		{"constante PI = 3; PI++;", "no se puede reasignar una constante: PI"},
+		{"constante PI = 3; si (verdadero) { PI = 4; }", "no se puede reasignar una constante: PI"},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		if expected, isInt := test.expected.(int); isInt {
+			e.testIntegerObject(evaluated, expected)
+		} else {
+			e.testErrorObject(evaluated, test.expected.(string))
+		}
+	}
+
+	// only the variable is constant, the list can still change
+	evaluated := e.evaluateTests("constante L = lista[1]; L:agregar(2); L;")
+	e.testIntArrayObject(evaluated, []int{1, 2})
+}
+
+func (e *EvaluatorTests) TestCharLiteral() {
+	tests := []tuple[string]{
+		{`'a';`, "a"},
+		{`tipo('a');`, "caracter"},
+		{`'a' == 'a';`, "verdadero"},
+		{`'a' == "a";`, "verdadero"},
+		{`"a" != 'a';`, "falso"},
+		{`'a' == "ab";`, "falso"},
+		{`'a' < 'b';`, "verdadero"},
+		{`'z' >= "a";`, "verdadero"},
+		{`'a' + 'b';`, "ab"},
+		{`"hol" + 'a';`, "hola"},
+		{`tipo('a' + 'b');`, "texto"},
+		{`mapa{'a' => 1}["a"];`, "1"},
+		{`coincidir 'b' { 'a' => 1, 'b' => 2 };`, "2"},
+		{`'\'' == "'";`, "verdadero"},
+		{`largo('a');`, "1"},
+		{`codigo('A');`, "65"},
+		{`'x':mayusculas();`, "X"},
+		{`'X':es_mayuscula();`, "verdadero"},
+		{`"a,b":separar(',');`, "[a, b]"},
+		{`"hola":contiene('l');`, "verdadero"},
+		{`"hola":empieza_con('h');`, "verdadero"},
+		{`"7":rellenar_izquierda(3, '0');`, "007"},
+		{`error('x');`, "Error: x"},
+		{`mismo('a', 'a');`, "verdadero"},
+		{`mismo('a', 'b');`, "falso"},
+		{`hash('a') == hash("a");`, "verdadero"},
+		{`a_json('a');`, `"a"`},
+		{`a_json(lista['a', "b"]);`, `["a","b"]`},
+		{`a_json(mapa{'a' => 'b'});`, `{"a":"b"}`},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.Equal(test.expected, evaluated.Inspect(), test.source)
+	}
+
+	evaluated := e.evaluateTests(`'a' * 'b';`)
+	e.testErrorObject(evaluated, "Operador desconocido: caracter * caracter")
+}
+
 func (e *EvaluatorTests) TestStringComparison() {
 	tests := []tuple[bool]{
 		{`"a" == "a"`, true},
@@ -944,6 +3037,15 @@ func (e *EvaluatorTests) evaluateTests(source string) obj.Object {
 	return evaluated
 }
 
+func (e *EvaluatorTests) evaluateWithNetwork(source string) obj.Object {
+	program := p.NewParser(l.NewLexer(source)).ParseProgam()
+	env := obj.NewEnviroment(nil)
+	env.AllowNetwork(time.Second)
+	evaluated := evaluator.Evaluate(program, env)
+	e.Assert().NotNil(evaluated)
+	return evaluated
+}
+
 func (e *EvaluatorTests) testBooleanObject(object obj.Object, expected bool) {
 	if !e.Assert().IsType(&obj.Bool{}, object) {
 		e.T().FailNow()
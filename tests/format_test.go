@@ -0,0 +1,95 @@
+package test
+
+import (
+	"aura/src/ast"
+	"os"
+	"path/filepath"
+)
+
+func (p *ParserTests) TestFormat() {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"var x = 5", "var x = 5;\n"},
+		{"funcion suma(a,b) { regresa a+b }", "funcion suma(a, b) {\n    regresa a + b;\n}\n"},
+		{"funcion suma(a, b) => a + b;", "funcion suma(a, b) => a + b;\n"},
+		{"f := |x| => x * 2", "f := |x| => x * 2;\n"},
+		{"f := |x| => { x }", "f := |x| => {\n    x;\n};\n"},
+		{`s := "di \"hola\"\n";`, "s := \"di \\\"hola\\\"\\n\";\n"},
+		{`"${nombre} y \${literal}";`, "\"${nombre} y \\${literal}\";\n"},
+		{"c := '\\'';", "c := '\\'';\n"},
+		{"x := 2.0; y := 1e300;", "x := 2.0;\ny := 1e+300;\n"},
+		{"lista[1,2,]; mapa{\"a\" => 1};", "lista[1, 2];\nmapa{\"a\" => 1};\n"},
+		{"(1 + 2) * 3 - (4 - 5);", "(1 + 2) * 3 - (4 - 5);\n"},
+		{"-(-x);", "-(-x);\n"},
+		{"(a ? b : c) + 1;", "(a ? b : c) + 1;\n"},
+		{"si (a) { 1 } si_no si (b) { 2 } si_no { 3 }", "si (a) {\n    1;\n} si_no si (b) {\n    2;\n} si_no {\n    3;\n}\n"},
+		{"si (a) { 1 }; -x;", "si (a) {\n    1;\n};\n\n-x;\n"},
+		{"por (var i = 0; i < 3; i++) { romper }", "por (var i = 0; i < 3; i++) {\n    romper;\n}\n"},
+		{"por (;;) { continuar }", "por (;;) {\n    continuar;\n}\n"},
+		{"dibujar(1, x = 2);", "dibujar(1, x = 2);\n"},
+		{"constante PI = 3.14", "constante PI = 3.14;\n"},
+		{"coincidir v { 0 => \"cero\", [a, _] si (a > 1) => a }", "coincidir v {\n    0 => \"cero\";\n    [a, _] si (a > 1) => a;\n}\n"},
+		{"clase A(x) { valor() => x; doble() { regresa x * 2 } }", "clase A(x) {\n    valor() => x;\n\n    doble() {\n        regresa x * 2;\n    }\n}\n"},
+		{"intentar { f() } excepto(e) { escribir(e) }", "intentar {\n    f();\n} excepto(e) {\n    escribir(e);\n}\n"},
+	}
+
+	for _, test := range tests {
+		parser, program := p.InitParserTests(test.source)
+		p.Assert().Empty(parser.Errors(), test.source)
+		p.Assert().Equal(test.expected, ast.Format(program), test.source)
+	}
+}
+
+func (p *ParserTests) TestFormatMultilineLiterals() {
+	long := "x := lista[\"uno\", \"dos\", \"tres\", \"cuatro\", \"cinco\", \"seis\", \"siete\", \"ocho\", \"nueve\"];"
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"x := lista[1, 2, 3];", "x := lista[1, 2, 3];\n"},
+		{"x := lista[\n1, 2]", "x := lista[\n    1,\n    2,\n];\n"},
+		{"x := mapa{\n\"a\" => 1, \"b\" => 2 + 3\n};", "x := mapa{\n    \"a\" => 1,\n    \"b\" => 2 + 3,\n};\n"},
+		{long, "x := lista[\n    \"uno\",\n    \"dos\",\n    \"tres\",\n    \"cuatro\",\n    \"cinco\",\n    \"seis\",\n    \"siete\",\n    \"ocho\",\n    \"nueve\",\n];\n"},
+		{"funcion f() {\n regresa lista[\nlista[1],\nmapa{1 => 2}]\n}", "funcion f() {\n    regresa lista[\n        lista[1],\n        mapa{1 => 2},\n    ];\n}\n"},
+		{"x := lista[lista[\n1]];", "x := lista[lista[\n    1,\n]];\n"},
+		{"x := lista[\n];", "x := lista[];\n"},
+	}
+
+	for _, test := range tests {
+		parser, program := p.InitParserTests(test.source)
+		p.Assert().Empty(parser.Errors(), test.source)
+
+		formatted := ast.Format(program)
+		p.Assert().Equal(test.expected, formatted, test.source)
+
+		// the multiline literals are kept when the source is formatted again
+		parser, program = p.InitParserTests(formatted)
+		p.Assert().Empty(parser.Errors(), test.source)
+		p.Assert().Equal(formatted, ast.Format(program), test.source)
+	}
+}
+
+func (p *ParserTests) TestFormatRoundTrip() {
+	paths, err := filepath.Glob("../examples/*.aura")
+	p.Require().NoError(err)
+	p.Require().NotEmpty(paths)
+
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		p.Require().NoError(err)
+
+		parser, program := p.InitParserTests(string(source))
+		p.Require().Empty(parser.Errors(), path)
+
+		// the formatted source is parsed to the same program
+		formatted := ast.Format(program)
+		parser, formattedProgram := p.InitParserTests(formatted)
+		p.Assert().Empty(parser.Errors(), path)
+		p.Assert().Equal(program.Str(), formattedProgram.Str(), path)
+
+		// formatting the formatted source does not change it
+		p.Assert().Equal(formatted, ast.Format(formattedProgram), path)
+	}
+}
@@ -2,7 +2,9 @@ package test
 
 import (
 	"aura/src/lexer"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"unicode/utf8"
 
 	"github.com/stretchr/testify/suite"
@@ -12,12 +14,14 @@ type LexerTests struct {
 	suite.Suite
 }
 
+// load the tokens without their position, the position is tested apart
 func (l *LexerTests) loadTokens(length int, source string) []*lexer.Token {
 	lex := lexer.NewLexer(source)
 	var tokens []*lexer.Token
 
 	for i := 0; i < length; i++ {
-		tokens = append(tokens, lex.NextToken())
+		token := lex.NextToken()
+		tokens = append(tokens, &lexer.Token{Token_type: token.Token_type, Literal: token.Literal})
 	}
 
 	return tokens
@@ -37,9 +41,66 @@ func (l *LexerTests) TestIllegalToken() {
 	l.Assert().Equal(expectedTokens, tokens)
 }
 
+func (l *LexerTests) TestCommentAtEndOfFile() {
+	source := "x // comentario sin salto de linea"
+	tokens := l.loadTokens(3, source)
+
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.IDENT, Literal: "x"},
+		{Token_type: lexer.EOF, Literal: ""},
+		{Token_type: lexer.EOF, Literal: ""},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+}
+
+func (l *LexerTests) TestHashComments() {
+	source := "# cabecera\nvar x = 5; # contador\n#\n\"# no es comentario\"; x # final"
+	tokens := l.loadTokens(9, source)
+
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.LET, Literal: "var"},
+		{Token_type: lexer.IDENT, Literal: "x"},
+		{Token_type: lexer.ASSING, Literal: "="},
+		{Token_type: lexer.INT, Literal: "5"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.STRING, Literal: "# no es comentario"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.IDENT, Literal: "x"},
+		{Token_type: lexer.EOF, Literal: ""},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+}
+
+func (l *LexerTests) TestBlockComments() {
+	source := "/* cabecera */ var x /* en\nvarias\nlineas */ = 5; /**/ \"/* no es comentario */\"; x /* sin cerrar"
+	tokens := l.loadTokens(10, source)
+
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.LET, Literal: "var"},
+		{Token_type: lexer.IDENT, Literal: "x"},
+		{Token_type: lexer.ASSING, Literal: "="},
+		{Token_type: lexer.INT, Literal: "5"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.STRING, Literal: "/* no es comentario */"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.IDENT, Literal: "x"},
+		{Token_type: lexer.ILLEGAL, Literal: "comentario sin cerrar"},
+		{Token_type: lexer.EOF, Literal: ""},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+
+	lex := lexer.NewLexer("/*\n*/ x /* sin\ncerrar")
+	l.Assert().Equal(&lexer.Token{Token_type: lexer.IDENT, Literal: "x", Line: 2, Column: 4}, lex.NextToken())
+	l.Assert().Equal(&lexer.Token{Token_type: lexer.ILLEGAL, Literal: "comentario sin cerrar", Line: 2, Column: 6}, lex.NextToken())
+}
+
 func (l *LexerTests) TestOneCharacterOperator() {
-	source := "+-/*<>!%="
-	tokens := l.loadTokens(utf8.RuneCountInString(source), source)
+	// a / followed by * starts a block comment, so they are separated
+	source := "+-/ *<>!%="
+	tokens := l.loadTokens(9, source)
 
 	expectedTokens := []*lexer.Token{
 		{Token_type: lexer.PLUS, Literal: "+"},
@@ -68,6 +129,20 @@ func (l *LexerTests) TestFloat() {
 	l.Assert().Equal(expectedTokens, tokens)
 }
 
+func (l *LexerTests) TestScientificNotation() {
+	source := "1.5e3 2E-4 6.02e+23 7"
+	tokens := l.loadTokens(4, source)
+
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.FLOAT, Literal: "1.5e3"},
+		{Token_type: lexer.FLOAT, Literal: "2E-4"},
+		{Token_type: lexer.FLOAT, Literal: "6.02e+23"},
+		{Token_type: lexer.INT, Literal: "7"},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+}
+
 func (l *LexerTests) TestClassDeclaration() {
 	source := `
 		clase Persona(nombre, edad) {}
@@ -138,8 +213,8 @@ func (l *LexerTests) TestDilimiters() {
 }
 
 func (l *LexerTests) TestAssingments() {
-	source := "var cinco =  5;"
-	tokens := l.loadTokens(5, source)
+	source := "var cinco =  5; constante PI = 3;"
+	tokens := l.loadTokens(10, source)
 
 	expectedTokens := []*lexer.Token{
 		{Token_type: lexer.LET, Literal: "var"},
@@ -147,6 +222,11 @@ func (l *LexerTests) TestAssingments() {
 		{Token_type: lexer.ASSING, Literal: "="},
 		{Token_type: lexer.INT, Literal: "5"},
 		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.CONST, Literal: "constante"},
+		{Token_type: lexer.IDENT, Literal: "PI"},
+		{Token_type: lexer.ASSING, Literal: "="},
+		{Token_type: lexer.INT, Literal: "3"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
 	}
 
 	l.Assert().Equal(expectedTokens, tokens)
@@ -323,6 +403,27 @@ func (l *LexerTests) TestUnderscoreVar() {
 	l.Assert().Equal(expectedTokens, tokens)
 }
 
+func (l *LexerTests) TestUnicodeIdentifiers() {
+	source := "var niño = 5; var añoΔ_2 = niño;"
+
+	tokens := l.loadTokens(10, source)
+
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.LET, Literal: "var"},
+		{Token_type: lexer.IDENT, Literal: "niño"},
+		{Token_type: lexer.ASSING, Literal: "="},
+		{Token_type: lexer.INT, Literal: "5"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.LET, Literal: "var"},
+		{Token_type: lexer.IDENT, Literal: "añoΔ_2"},
+		{Token_type: lexer.ASSING, Literal: "="},
+		{Token_type: lexer.IDENT, Literal: "niño"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+}
+
 func (l *LexerTests) TestString() {
 	source := `
 		"foo";
@@ -340,6 +441,194 @@ func (l *LexerTests) TestString() {
 	l.Assert().Equal(expectedTokens, tokens)
 }
 
+func (l *LexerTests) TestStringEscapes() {
+	source := `"a\nb\tc\rd"; "dijo \"hola\""; "it's"; "\\"; "\q"; "\${x}";`
+
+	tokens := l.loadTokens(12, source)
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.STRING, Literal: "a\nb\tc\rd"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.STRING, Literal: `dijo "hola"`},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.STRING, Literal: "it's"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.STRING, Literal: `\`},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		// the escape sequences that are not valid are kept
+		{Token_type: lexer.STRING, Literal: `\q`},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.STRING, Literal: "${x}"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+}
+
+func (l *LexerTests) TestCharLiteral() {
+	source := `'a' 'ñ' '\n' '\\' '\'' 'ab' 'hola' '' "'" 'a`
+	tokens := l.loadTokens(11, source)
+
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.CHAR, Literal: "a"},
+		{Token_type: lexer.CHAR, Literal: "ñ"},
+		{Token_type: lexer.CHAR, Literal: "\n"},
+		{Token_type: lexer.CHAR, Literal: `\`},
+		{Token_type: lexer.CHAR, Literal: "'"},
+		// the single quotes with more or less than one character are illegal
+		{Token_type: lexer.ILLEGAL, Literal: "caracter no valido 'ab', se esperaba un solo caracter"},
+		{Token_type: lexer.ILLEGAL, Literal: "caracter no valido 'hola', se esperaba un solo caracter"},
+		{Token_type: lexer.ILLEGAL, Literal: "caracter no valido '', se esperaba un solo caracter"},
+		{Token_type: lexer.STRING, Literal: "'"},
+		{Token_type: lexer.ILLEGAL, Literal: "caracter sin cerrar"},
+		{Token_type: lexer.EOF, Literal: ""},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+}
+
+func (l *LexerTests) TestInterpolatedString() {
+	source := `"hola ${nombre}"; "${ "}" }"; "\${nombre}";`
+
+	tokens := l.loadTokens(6, source)
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.TEMPLATE, Literal: "hola ${nombre}"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.TEMPLATE, Literal: `${ "}" }`},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+		{Token_type: lexer.STRING, Literal: "${nombre}"},
+		{Token_type: lexer.SEMICOLON, Literal: ";"},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+}
+
+func (l *LexerTests) TestLexerPosition() {
+	source := "var x = 5;\nvar año = 10;"
+	lex := lexer.NewLexer(source)
+
+	l.Assert().Equal(0, lex.Offset())
+	l.Assert().Equal(1, lex.Line())
+	l.Assert().Equal(1, lex.Column())
+
+	for i := 0; i < 6; i++ {
+		lex.NextToken()
+	}
+
+	// the lexer is now in the space after the second var
+	l.Assert().Equal(14, lex.Offset())
+	l.Assert().Equal(2, lex.Line())
+	l.Assert().Equal(4, lex.Column())
+
+	lex.NextToken()
+	l.Assert().Equal(19, lex.Offset())
+	l.Assert().Equal(2, lex.Line())
+	l.Assert().Equal(8, lex.Column())
+}
+
+func (l *LexerTests) TestTokenPosition() {
+	source := "var año = 10; // comentario\n\tx := \"ñandú\" +\n  año;"
+	lex := lexer.NewLexer(source)
+
+	expected := []struct {
+		literal string
+		line    int
+		column  int
+	}{
+		{"var", 1, 1},
+		{"año", 1, 5},
+		{"=", 1, 9},
+		{"10", 1, 11},
+		{";", 1, 13},
+		{"x", 2, 2},
+		{":=", 2, 4},
+		{"ñandú", 2, 7},
+		{"+", 2, 15},
+		{"año", 3, 3},
+		{";", 3, 6},
+		{"", 3, 7},
+	}
+
+	for _, test := range expected {
+		token := lex.NextToken()
+		l.Assert().Equal(test.literal, token.Literal)
+		l.Assert().Equal(test.line, token.Line, test.literal)
+		l.Assert().Equal(test.column, token.Column, test.literal)
+	}
+}
+
+func (l *LexerTests) TestLexerAt() {
+	source := "var x = 5;\nvar y = 10;"
+	lex := lexer.NewLexerAt(source, 11, 2, 1)
+
+	l.Assert().Equal(2, lex.Line())
+	l.Assert().Equal(1, lex.Column())
+
+	var tokens []*lexer.Token
+	for i := 0; i < 6; i++ {
+		tokens = append(tokens, lex.NextToken())
+	}
+
+	expectedTokens := []*lexer.Token{
+		{Token_type: lexer.LET, Literal: "var", Line: 2, Column: 1},
+		{Token_type: lexer.IDENT, Literal: "y", Line: 2, Column: 5},
+		{Token_type: lexer.ASSING, Literal: "=", Line: 2, Column: 7},
+		{Token_type: lexer.INT, Literal: "10", Line: 2, Column: 9},
+		{Token_type: lexer.SEMICOLON, Literal: ";", Line: 2, Column: 11},
+		{Token_type: lexer.EOF, Literal: "", Line: 2, Column: 12},
+	}
+
+	l.Assert().Equal(expectedTokens, tokens)
+}
+
+func (l *LexerTests) TestLexerFromLine() {
+	source := "var x = 5;\nvar y = 10;\nx + y;"
+	lex := lexer.NewLexerFromLine(source, 3)
+
+	l.Assert().Equal(23, lex.Offset())
+	l.Assert().Equal(3, lex.Line())
+	l.Assert().Equal(1, lex.Column())
+	l.Assert().Equal(&lexer.Token{Token_type: lexer.IDENT, Literal: "x", Line: 3, Column: 1}, lex.NextToken())
+
+	lex = lexer.NewLexerFromLine(source, 10)
+	l.Assert().Equal(len(source), lex.Offset())
+	l.Assert().Equal(lexer.EOF, lex.NextToken().Token_type)
+}
+
+func (l *LexerTests) TestLexerFromReader() {
+	source := `
+		var año = "señor ñandú";
+		si (año >= 10) { regresa "¡olé!"; }
+	`
+
+	// the one byte reader splits all the multibyte characters between reads
+	lex := lexer.NewLexerFromReader(iotest.OneByteReader(strings.NewReader(source)))
+	expected := lexer.NewLexer(source)
+
+	for {
+		token := lex.NextToken()
+		l.Assert().Equal(expected.NextToken(), token)
+		l.Assert().Equal(expected.Offset(), lex.Offset())
+		if token.Token_type == lexer.EOF {
+			break
+		}
+	}
+
+	l.Assert().Nil(lex.Err())
+}
+
+func (l *LexerTests) TestLexerFromReaderBigSource() {
+	source := strings.Repeat("x := lista[1, 2.5, \"ñ\"]; x[0] += 1;\n", 5000)
+	lex := lexer.NewLexerFromReader(strings.NewReader(source))
+
+	count := 0
+	for token := lex.NextToken(); token.Token_type != lexer.EOF; token = lex.NextToken() {
+		count++
+	}
+
+	l.Assert().Equal(5000*18, count)
+	l.Assert().Equal(5001, lex.Line())
+}
+
 func TestLexerSuite(t *testing.T) {
 	suite.Run(t, new(LexerTests))
 }
@@ -0,0 +1,74 @@
+package test
+
+func (e *EvaluatorTests) TestNestedEquality() {
+	tests := []tuple[bool]{
+		{"lista[lista[1], lista[2]] == lista[lista[1], lista[2]];", true},
+		{"lista[lista[1], lista[2]] == lista[lista[1], lista[3]];", false},
+		{"lista[lista[1], lista[2]] != lista[lista[2], lista[1]];", true},
+		{"lista[lista[1]] == lista[lista[1.0]];", true},
+		{"lista[lista[1]] == lista[lista[\"1\"]];", false},
+		{"lista['a', lista[\"b\"]] == lista[\"a\", lista['b']];", true},
+		{"lista[lista[]] == lista[];", false},
+		{"lista[nulo, lista[nulo]] == lista[nulo, lista[nulo]];", true},
+		{"mapa{\"a\" => lista[1, 2]} == mapa{\"a\" => lista[1, 2]};", true},
+		{"mapa{\"a\" => 1, \"b\" => 2} == mapa{\"b\" => 2, \"a\" => 1};", true},
+		{"mapa{\"a\" => mapa{\"b\" => 1}} == mapa{\"a\" => mapa{\"b\" => 2}};", false},
+		{"conjunto(lista[1, 2]) == conjunto(lista[2, 1]);", true},
+		{"congelar(lista[lista[1]]) == lista[lista[1]];", true},
+		{"f := funcion() {}; lista[f] == lista[f];", true},
+		{"lista[funcion() {}] == lista[funcion() {}];", false},
+		{"a := lista[1]; a:agregar(a); b := lista[1]; b:agregar(b); a == b;", true},
+	}
+
+	for _, test := range tests {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+}
+
+func (e *EvaluatorTests) TestNestedListMethods() {
+	booleans := []tuple[bool]{
+		{"lista[1, lista[2]]:contiene(lista[2]);", true},
+		{"lista[1, lista[2]]:contiene(lista[3]);", false},
+		{"lista[mapa{\"a\" => lista[1]}]:contiene(mapa{\"a\" => lista[1.0]});", true},
+		{"lista[1, 2]:contiene(2.0);", true},
+	}
+
+	for _, test := range booleans {
+		evaluated := e.evaluateTests(test.source)
+		e.testBooleanObject(evaluated, test.expected)
+	}
+
+	integers := []tuple[int]{
+		{"lista[1, lista[2], lista[2]]:indice(lista[2]);", 1},
+		{"lista[1, lista[2]]:indice(lista[3]);", -1},
+		{"lista[mapa{\"a\" => 1}]:indice(mapa{\"a\" => 1});", 0},
+		{"largo(lista[lista[1], lista[1], lista[1, 2], mapa{\"a\" => 1}, mapa{\"a\" => 1}]:unico());", 3},
+	}
+
+	for _, test := range integers {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntegerObject(evaluated, test.expected)
+	}
+
+	lists := []tuple[[]int]{
+		{"lista[1, lista[2], 1, lista[2], 3]:unico():aplanar_profundo();", []int{1, 2, 3}},
+		{"lista[1, lista[2, lista[3, lista[]]], 4]:aplanar_profundo();", []int{1, 2, 3, 4}},
+		{"lista[]:aplanar_profundo();", []int{}},
+	}
+
+	for _, test := range lists {
+		evaluated := e.evaluateTests(test.source)
+		e.testIntArrayObject(evaluated, test.expected)
+	}
+
+	// the original list is not changed
+	evaluated := e.evaluateTests("original := lista[lista[1], 2]; original:aplanar_profundo(); original[0][0];")
+	e.testIntegerObject(evaluated, 1)
+
+	evaluated = e.evaluateTests("l := lista[1]; l:agregar(l); l:aplanar_profundo();")
+	e.testErrorObject(evaluated, "no se puede aplanar una lista que se contiene a si misma")
+
+	evaluated = e.evaluateTests("lista[1]:indice();")
+	e.testErrorObject(evaluated, "numero incorrecto de argumentos para indice, se recibieron 0, se requieren 1")
+}
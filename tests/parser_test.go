@@ -6,6 +6,7 @@ import (
 	"aura/src/parser"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -43,6 +44,15 @@ func (p *ParserTests) TestParseProgram() {
 	p.Assert().Equal(1, len(program.Staments))
 }
 
+func (p *ParserTests) TestEmptyProgram() {
+	for _, source := range []string{"", "   ", "// just a comment", "\n\t// comentario\n  "} {
+		parser, program := p.InitParserTests(source)
+		p.Assert().NotNil(program)
+		p.Assert().Empty(program.Staments)
+		p.Assert().Empty(parser.Errors())
+	}
+}
+
 func (p *ParserTests) TestLetStatements() {
 	source := `
 		var x = 5;
@@ -73,6 +83,24 @@ func (p *ParserTests) TestLetStatements() {
 	}
 }
 
+func (p *ParserTests) TestConstStatements() {
+	parser, program := p.InitParserTests("constante PI = 3; constante nombre = \"aura\";")
+	p.Empty(parser.Errors())
+	p.Len(program.Staments, 2)
+
+	constant, isConst := program.Staments[0].(*ast.ConstStatement)
+	if !p.True(isConst) {
+		p.T().FailNow()
+	}
+	p.Equal("constante", constant.TokenLiteral())
+	p.testIdentifier(constant.Name, "PI")
+	p.testLiteralExpression(constant.Value, 3)
+	p.Equal("constante nombre = aura;", program.Staments[1].Str())
+
+	parser, _ = p.InitParserTests("constante PI 3;")
+	p.Equal([]string{"se esperaba que el siguient token fuera = pero se obtuvo INT en linea 1, columna 14"}, parser.Errors())
+}
+
 func (p *ParserTests) TestNamesInLetStatements() {
 	source := `
 		var x = 5;
@@ -109,6 +137,159 @@ func (p *ParserTests) TestParseErrors() {
 	if !p.Assert().Equal(1, len(parser.Errors())) {
 		p.T().Fail()
 	}
+
+	parser, _ = p.InitParserTests("5 := 2;")
+	p.Assert().NotEmpty(parser.Errors())
+
+	parser, _ = p.InitParserTests("var x = 1;\nvar año = 2;\n  x + ];")
+	p.Assert().Equal([]string{"no se encontro ninguna funcion para parsear ] en linea 3, columna 7"}, parser.Errors())
+
+	parser, _ = p.InitParserTests("var x = 1;\nvar año 2;")
+	p.Assert().Equal([]string{"se esperaba que el siguient token fuera = pero se obtuvo INT en linea 2, columna 9"}, parser.Errors())
+}
+
+func (p *ParserTests) TestGroupEdgeCases() {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"x := ();", "expresion vacia entre parentesis en linea 1, columna 6"},
+		{"x := () + 1;", "expresion vacia entre parentesis en linea 1, columna 6"},
+		{"x := (1, 2);", "las tuplas no estan soportadas, se esperaba ) pero se obtuvo , en linea 1, columna 8"},
+		{"x := (1, (2, 3), 4) * 2; y := 1;", "las tuplas no estan soportadas, se esperaba ) pero se obtuvo , en linea 1, columna 8"},
+	}
+
+	for _, test := range tests {
+		parser, _ := p.InitParserTests(test.source)
+		p.Equal([]string{test.expected}, parser.Errors(), test.source)
+	}
+
+	// a call without arguments is not an empty group
+	parser, program := p.InitParserTests("f(); (1 + 2) * 3;")
+	p.testProgramStatements(parser, program, 2)
+	p.Equal("((1 + 2) * 3)", program.Staments[1].Str())
+}
+
+func (p *ParserTests) TestTrailingCommas() {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"f(1, 2, 3,);", "f(1, 2, 3)"},
+		{"f(\n\t1,\n\t2,\n);", "f(1, 2)"},
+		{"lista[1, 2,];", "1, 2"},
+		{"mapa{1 => 2,};", "mapa{1 => 2}"},
+		{"mapa{\n\t\"a\" => 1,\n\t\"b\" => 2,\n};", "mapa{a => 1, b => 2}"},
+	}
+
+	for _, test := range tests {
+		parser, program := p.InitParserTests(test.source)
+		p.testProgramStatements(parser, program, 1)
+		p.Equal(test.expected, program.Staments[0].Str(), test.source)
+	}
+
+	parser, program := p.InitParserTests("var suma = funcion(a, b,) { regresa a + b; };")
+	p.Empty(parser.Errors())
+	function := program.Staments[0].(*ast.LetStatement).Value.(*ast.Function)
+	p.Len(function.Parameters, 2)
+
+	parser, program = p.InitParserTests("var doble = |x,| => x * 2;")
+	p.Empty(parser.Errors())
+	p.Len(program.Staments[0].(*ast.LetStatement).Value.(*ast.ArrowFunc).Params, 1)
+
+	// a comma without a value before it is still an error
+	for _, source := range []string{"f(,);", "f(1,,);", "funcion(,) {};", "mapa{1 => 2,,};"} {
+		parser, _ := p.InitParserTests(source)
+		p.NotEmpty(parser.Errors(), source)
+	}
+}
+
+func (p *ParserTests) TestNamedArguments() {
+	parser, program := p.InitParserTests("dibujar(1, y = 2, color = \"rojo\");")
+	p.testProgramStatements(parser, program, 1)
+
+	call := program.Staments[0].(*ast.ExpressionStament).Expression.(*ast.Call)
+	p.Len(call.Arguments, 1)
+	p.Len(call.Named, 2)
+	p.Equal("y", call.Named[0].Name.Value)
+	p.Equal("color", call.Named[1].Name.Value)
+	p.Equal("dibujar(1, y = 2, color = rojo)", call.Str())
+
+	// only a variable can be the name of an argument
+	parser, program = p.InitParserTests("f(a[0] = 1);")
+	p.testProgramStatements(parser, program, 1)
+	call = program.Staments[0].(*ast.ExpressionStament).Expression.(*ast.Call)
+	p.Len(call.Arguments, 1)
+	p.Empty(call.Named)
+
+	parser, _ = p.InitParserTests("dibujar(x = 1, 2);")
+	p.Equal([]string{"los argumentos con nombre deben ir despues de los posicionales, se obtuvo 2 en linea 1, columna 8"}, parser.Errors())
+}
+
+func (p *ParserTests) TestUnexpectedEOF() {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"var x = 5 +", "fin de archivo inesperado, se esperaba una expresion"},
+		{"x := ", "fin de archivo inesperado, se esperaba una expresion"},
+		{"regresa 5 *", "fin de archivo inesperado, se esperaba una expresion"},
+		{"si (", "fin de archivo inesperado, se esperaba una expresion"},
+		{"si (x", "fin de archivo inesperado, se esperaba )"},
+		{"si (x) { 1", "fin de archivo inesperado, se esperaba }"},
+		{"suma(1, ", "fin de archivo inesperado, se esperaba una expresion"},
+		{"suma(1", "fin de archivo inesperado, se esperaba )"},
+		{"coincidir x { 1 => 2;", "fin de archivo inesperado, se esperaba }"},
+	}
+
+	for _, test := range tests {
+		parser, _ := p.InitParserTests(test.source)
+		p.Assert().Equal([]string{test.expected}, parser.Errors(), test.source)
+	}
+}
+
+func (p *ParserTests) TestPartialNodesStr() {
+	token := l.NewToken(l.IDENT, "x")
+	name := ast.NewIdentifier(token, "x")
+	tests := []struct {
+		node     ast.ASTNode
+		expected string
+	}{
+		{ast.NewLetStatement(token, name, nil), "var x = <error>;"},
+		{ast.NewReturnStatement(token, nil), "return <error>;"},
+		{ast.NewIf(token, nil, nil, nil), "si <error> <error> "},
+		{ast.NewCall(token, nil, name, nil), "<error>(x, <error>)"},
+		{ast.Newinfix(token, nil, "+", name), "(x + <error>)"},
+		{ast.NewPrefix(token, "-", nil), "(- <error>)"},
+		{ast.NewFunction(token, nil, nil), "funcion () <error>"},
+	}
+
+	for _, test := range tests {
+		p.Assert().Equal(test.expected, test.node.Str())
+	}
+
+	// the program of a source with errors can always be printed
+	sources := []string{"var x = ;", "si () { 1 }", "suma(1, ", "x := 5 +", "|x| => "}
+	for _, source := range sources {
+		parser, program := p.InitParserTests(source)
+		p.Assert().NotEmpty(parser.Errors(), source)
+		p.Assert().NotPanics(func() { program.Str() }, source)
+	}
+}
+
+func (p *ParserTests) TestMaxDepth() {
+	source := strings.Repeat("(", 10000) + "1" + strings.Repeat(")", 10000)
+	deep, program := p.InitParserTests(source)
+	p.Assert().NotNil(program)
+	p.Assert().Equal([]string{"la expresion excede la profundidad maxima de 1000 niveles"}, deep.Errors())
+
+	interpolated := parser.NewParser(l.NewLexer(`"${` + strings.Repeat("(", 20) + "1" + strings.Repeat(")", 20) + `}"`))
+	interpolated.SetMaxDepth(10)
+	interpolated.ParseProgam()
+	p.Assert().Equal([]string{"la expresion excede la profundidad maxima de 10 niveles"}, interpolated.Errors())
+
+	nested, program := p.InitParserTests(strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100))
+	p.testProgramStatements(nested, program, 1)
 }
 
 func (p *ParserTests) TestReturnStatement() {
@@ -143,6 +324,16 @@ func (p *ParserTests) TestReturnStatement() {
 	}
 }
 
+func (p *ParserTests) TestTryValue() {
+	parser, program := p.InitParserTests(`intentar(entero("a"));`)
+	p.testProgramStatements(parser, program, 1)
+
+	try := (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.TryValue)
+	call := try.Value.(*ast.Call)
+	p.testIdentifier(call.Function, "entero")
+	p.Assert().Equal(`intentar(entero(a))`, try.Str())
+}
+
 func (p *ParserTests) TestTryExp() {
 	source := `
 		intentar {
@@ -207,6 +398,23 @@ func (p *ParserTests) TestClassStatement() {
 	p.Assert().Equal(1, len(class.Methods))
 }
 
+func (p *ParserTests) TestMalformedClassMethod() {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"clase A(x) { saludar { regresa x; } } 1;", "se esperaba que el siguient token fuera ( pero se obtuvo { en linea 1, columna 22"},
+		{"clase A(x) { saludar() regresa x; } 1;", "se esperaba que el siguient token fuera { pero se obtuvo regresa en linea 1, columna 24"},
+		{"clase A(x) { 5() { regresa x; } } 1;", "se esperaba el nombre de un metodo pero se obtuvo 5"},
+		{"clase A(x) { saludar() { regresa x; }", "fin de archivo inesperado, se esperaba }"},
+	}
+
+	for _, test := range tests {
+		parser, _ := p.InitParserTests(test.source)
+		p.Assert().Equal([]string{test.expected}, parser.Errors(), test.source)
+	}
+}
+
 func (p *ParserTests) TestClassCall() {
 	source := `
 		var p = nuevo Persona("joao", "informatica");
@@ -228,6 +436,90 @@ func (p *ParserTests) TestIntegerExpressions() {
 	p.testLiteralExpression(expressionStament.Expression, 5)
 }
 
+func (p *ParserTests) TestDigitSeparators() {
+	parser, program := p.InitParserTests("1_000_000; 12_3; 3.141_592; 1_000.5;")
+	p.testProgramStatements(parser, program, 4)
+
+	integers := []int{1000000, 123}
+	for i, expected := range integers {
+		integer := program.Staments[i].(*ast.ExpressionStament).Expression.(*ast.Integer)
+		p.Equal(expected, *integer.Value)
+	}
+
+	floats := []float64{3.141592, 1000.5}
+	for i, expected := range floats {
+		float := program.Staments[i+2].(*ast.ExpressionStament).Expression.(*ast.FloatExp)
+		p.Equal(expected, float.Value)
+	}
+
+	invalid := []string{"100_;", "1__0;", "1_.5;", "1._5;", "2.5_;"}
+	for _, source := range invalid {
+		parser, _ := p.InitParserTests(source)
+		p.NotEmpty(parser.Errors(), source)
+	}
+
+	parser, _ = p.InitParserTests("x := 1__0;")
+	p.Equal([]string{"separador de digitos no valido en 1__0 en linea 1, columna 6"}, parser.Errors())
+}
+
+func (p *ParserTests) TestScientificNotation() {
+	parser, program := p.InitParserTests("1.5e3; 2E-4; 6.02e23; 1e+2; 1_000e-3;")
+	p.testProgramStatements(parser, program, 5)
+
+	expected := []float64{1500, 0.0002, 6.02e23, 100, 1}
+	for i, value := range expected {
+		float := program.Staments[i].(*ast.ExpressionStament).Expression.(*ast.FloatExp)
+		p.Equal(value, float.Value)
+	}
+
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"1.5e;", "exponente no valido en 1.5e, se esperaban digitos despues de e en linea 1, columna 1"},
+		{"x := 1.5e+;", "exponente no valido en 1.5e+, se esperaban digitos despues de e+ en linea 1, columna 6"},
+		{"2E-;", "exponente no valido en 2E-, se esperaban digitos despues de E- en linea 1, columna 1"},
+	}
+
+	for _, test := range tests {
+		parser, _ := p.InitParserTests(test.source)
+		p.Equal([]string{test.expected}, parser.Errors(), test.source)
+	}
+}
+
+func (p *ParserTests) TestCharLiteral() {
+	parser, program := p.InitParserTests(`'a'; '\n'; 'ñ';`)
+	p.testProgramStatements(parser, program, 3)
+
+	expected := []rune{'a', '\n', 'ñ'}
+	for i, value := range expected {
+		char := program.Staments[i].(*ast.ExpressionStament).Expression.(*ast.Char)
+		p.Equal(value, char.Value)
+	}
+
+	p.Equal(`'\n'`, program.Staments[1].Str())
+
+	// the single quotes with more than one character are not characters
+	parser, _ = p.InitParserTests(`x := 'ab';`)
+	p.Contains(parser.Errors(), "caracter no valido 'ab', se esperaba un solo caracter en linea 1, columna 6")
+}
+
+func (p *ParserTests) TestIllegalTokens() {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"x := 'a", "caracter sin cerrar en linea 1, columna 6"},
+		{"x := 1 @ 2;", "caracter no valido @ en linea 1, columna 8"},
+		{"x := 1;\n/* comentario", "comentario sin cerrar en linea 2, columna 1"},
+	}
+
+	for _, test := range tests {
+		parser, _ := p.InitParserTests(test.source)
+		p.Contains(parser.Errors(), test.expected, test.source)
+	}
+}
+
 func (p *ParserTests) TestPrefixExpressions() {
 	source := "!5; -15; !verdadero; !falso;"
 	parser, program := p.InitParserTests(source)
@@ -298,6 +590,83 @@ func (p *ParserTests) TestArrayExpression() {
 	}
 }
 
+func (p *ParserTests) TestComprehensions() {
+	source := `
+		lista[x * 2 por (x en valores) si (x > 0)];
+		mapa{x => x * 2 por (x en valores)};
+	`
+	parser, program := p.InitParserTests(source)
+	p.testProgramStatements(parser, program, 2)
+
+	list := (program.Staments[0].(*ast.ExpressionStament)).Expression
+	if !p.Assert().IsType(&ast.ListComprehension{}, list) {
+		p.T().FailNow()
+	}
+
+	comprehension := list.(*ast.ListComprehension)
+	p.testInfixExpression(comprehension.Element, "x", "*", 2)
+	p.testInfixExpression(comprehension.Condition, "x", ">", 0)
+	p.Assert().Equal("x en valores", comprehension.Iteration.Str())
+
+	hashMap := (program.Staments[1].(*ast.ExpressionStament)).Expression
+	if !p.Assert().IsType(&ast.MapComprehension{}, hashMap) {
+		p.T().FailNow()
+	}
+
+	mapComprehension := hashMap.(*ast.MapComprehension)
+	p.testIdentifier(mapComprehension.Pair.Key, "x")
+	p.testInfixExpression(mapComprehension.Pair.Value, "x", "*", 2)
+	p.Assert().Nil(mapComprehension.Condition)
+}
+
+func (p *ParserTests) TestClassicFor() {
+	source := `
+		por (var i = 0; i < 10; i++) { i; }
+		por (;;) { romper; }
+	`
+	parser, program := p.InitParserTests(source)
+	p.testProgramStatements(parser, program, 2)
+
+	loop := (program.Staments[0].(*ast.ExpressionStament)).Expression
+	if !p.Assert().IsType(&ast.ClassicFor{}, loop) {
+		p.T().FailNow()
+	}
+
+	classicFor := loop.(*ast.ClassicFor)
+	p.IsType(&ast.LetStatement{}, classicFor.Init)
+	p.testInfixExpression(classicFor.Condition, "i", "<", 10)
+	p.IsType(&ast.Suffix{}, classicFor.Post)
+	p.Equal(1, len(classicFor.Body.Staments))
+
+	empty := (program.Staments[1].(*ast.ExpressionStament)).Expression.(*ast.ClassicFor)
+	p.Nil(empty.Init)
+	p.Nil(empty.Condition)
+	p.Nil(empty.Post)
+}
+
+func (p *ParserTests) TestLoopsRoundTrip() {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"por (i en rango(10)) { escribir(i); }", "por (i en rango(10)) { escribir(i) }"},
+		{"por(x en xs) { x }", "por (x en xs) { x }"},
+		{"por (var i = 0; i < 3; i++) { escribir(i); }", "por (var i = 0; (i < 3); i++) { escribir(i) }"},
+		{"mientras (i < 3) { i++; }", "mientras ((i < 3)) { i++ }"},
+	}
+
+	for _, test := range tests {
+		parser, program := p.InitParserTests(test.source)
+		p.testProgramStatements(parser, program, 1)
+		p.Assert().Equal(test.expected, program.Str())
+
+		// the string representation is valid source that produces the same program
+		reparsed, program := p.InitParserTests(program.Str())
+		p.testProgramStatements(reparsed, program, 1)
+		p.Assert().Equal(test.expected, program.Str())
+	}
+}
+
 func (p *ParserTests) TestCallExpression() {
 	source := "suma(1, 2 * 3, 4 + 5);"
 	parser, program := p.InitParserTests(source)
@@ -314,6 +683,47 @@ func (p *ParserTests) TestCallExpression() {
 	p.testInfixExpression(call.Arguments[2], 4, "+", 5)
 }
 
+func (p *ParserTests) TestConstructorCall() {
+	source := "lista(rango(5));"
+	parser, program := p.InitParserTests(source)
+	p.testProgramStatements(parser, program, 1)
+
+	call := (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.Call)
+	p.testIdentifier(call.Function, "lista")
+	p.Assert().Equal(1, len(call.Arguments))
+	p.Assert().IsType(&ast.Call{}, call.Arguments[0])
+}
+
+func (p *ParserTests) TestMatchExpression() {
+	source := `coincidir valor { [a, _] => a; mapa{"n" => n} => { n }, -1 => 0; otro => otro }`
+	parser, program := p.InitParserTests(source)
+	p.testProgramStatements(parser, program, 1)
+
+	match := (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.Match)
+	p.testIdentifier(match.Value, "valor")
+	p.Assert().Equal(4, len(match.Arms))
+
+	list := match.Arms[0].Pattern.(*ast.ListPattern)
+	p.Assert().Equal(2, len(list.Elements))
+	p.testIdentifier(list.Elements[0], "a")
+	p.testIdentifier(list.Elements[1], "_")
+
+	mapPattern := match.Arms[1].Pattern.(*ast.MapPattern)
+	p.Assert().Equal(1, len(mapPattern.Pairs))
+	p.testIdentifier(mapPattern.Pairs[0].Value, "n")
+
+	p.Assert().IsType(&ast.Prefix{}, match.Arms[2].Pattern)
+	p.testIdentifier(match.Arms[3].Pattern, "otro")
+	p.Assert().Nil(match.Arms[3].Guard)
+
+	_, program = p.InitParserTests("coincidir valor { x si (x > 10) => x }")
+	match = (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.Match)
+	p.testInfixExpression(match.Arms[0].Guard, "x", ">", 10)
+
+	invalid, _ := p.InitParserTests("coincidir valor { + => 1 }")
+	p.Assert().Contains(invalid.Errors(), "patron no valido: +")
+}
+
 func (p *ParserTests) TestFunctionLiteral() {
 	source := "funcion(x, y) { x + y }"
 	parser, program := p.InitParserTests(source)
@@ -443,13 +853,46 @@ func (p *ParserTests) TestIfExpression() {
 
 	p.Assert().NotNil(ifExpression.Alternative)
 	p.Assert().IsType(&ast.Block{}, ifExpression.Alternative)
-	p.Assert().Equal(1, len(ifExpression.Alternative.Staments))
+	alternative := ifExpression.Alternative.(*ast.Block)
+	p.Assert().Equal(1, len(alternative.Staments))
 
-	alternativeStament := ifExpression.Alternative.Staments[0].(*ast.ExpressionStament)
+	alternativeStament := alternative.Staments[0].(*ast.ExpressionStament)
 	p.Assert().NotNil(alternativeStament.Expression)
 	p.testIdentifier(alternativeStament.Expression, "w")
 }
 
+func (p *ParserTests) TestElseIfExpression() {
+	source := "si (x > 0) { 1 } si_no si (x < 0) { 2 } si_no si (x == 0) { 3 } si_no { 4 }"
+	parser, program := p.InitParserTests(source)
+	p.testProgramStatements(parser, program, 1)
+
+	ifExpression := (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.If)
+	p.testInfixExpression(ifExpression.Condition, "x", ">", 0)
+
+	// each si_no si is an if in the alternative of the previous one
+	second, isIf := ifExpression.Alternative.(*ast.If)
+	if !p.True(isIf) {
+		p.T().FailNow()
+	}
+	p.testInfixExpression(second.Condition, "x", "<", 0)
+
+	third, isIf := second.Alternative.(*ast.If)
+	if !p.True(isIf) {
+		p.T().FailNow()
+	}
+	p.testInfixExpression(third.Condition, "x", "==", 0)
+	p.IsType(&ast.Block{}, third.Alternative)
+	p.Equal("si (x > 0) 1 si_no si (x < 0) 2 si_no si (x == 0) 3 si_no 4", ifExpression.Str())
+
+	parser, program = p.InitParserTests("si (a) { 1 } si_no si (b) { 2 }")
+	p.testProgramStatements(parser, program, 1)
+	ifExpression = (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.If)
+	p.Nil(ifExpression.Alternative.(*ast.If).Alternative)
+
+	parser, _ = p.InitParserTests("si (a) { 1 } si_no si { 2 }")
+	p.NotEmpty(parser.Errors())
+}
+
 func (p *ParserTests) TestBooleanExpressions() {
 	source := "verdadero; falso;"
 	parser, program := p.InitParserTests(source)
@@ -483,6 +926,17 @@ func (p *ParserTests) TestOperatorPrecedence() {
 		{source: "-(5 + 5);", expected: "(- (5 + 5))", expectedCount: 1},
 		{source: "a + suma(b * c) + d;", expected: "((a + suma((b * c))) + d)", expectedCount: 1},
 		{source: "a + suma(b * c) + d;", expected: "((a + suma((b * c))) + d)", expectedCount: 1},
+		{source: "a.b.c;", expected: "a.b.c", expectedCount: 1},
+		{source: "a.b();", expected: "a.b()", expectedCount: 1},
+		{source: "a.b + c;", expected: "(a.b + c)", expectedCount: 1},
+		{source: "a.b.c * d;", expected: "(a.b.c * d)", expectedCount: 1},
+		{source: "-obj.campo;", expected: "(- obj.campo)", expectedCount: 1},
+		{source: "l:longitud() + 1;", expected: "(l:longitud() + 1)", expectedCount: 1},
+		{source: "a * b++;", expected: "(a * b++)", expectedCount: 1},
+		{source: "-a++;", expected: "(- a++)", expectedCount: 1},
+		{source: "(a + b)**;", expected: "(a + b)**", expectedCount: 1},
+		{source: "arr[0]++;", expected: "arr[0]++", expectedCount: 1},
+		{source: "obj.campo--;", expected: "obj.campo--", expectedCount: 1},
 		{
 			source:        "suma(a, b, 1, 2 * 3, 4 + 5, suma(6, 7 * 8))",
 			expected:      "suma(a, b, 1, (2 * 3), (4 + 5), suma(6, (7 * 8)))",
@@ -496,6 +950,48 @@ func (p *ParserTests) TestOperatorPrecedence() {
 		p.testProgramStatements(parser, program, source.expectedCount)
 		p.Assert().Equal(source.expected, program.Str())
 	}
+
+	// a.b.c is grouped to the left -> (a.b).c
+	_, program := p.InitParserTests("a.b.c;")
+	field := (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.ClassFieldCall)
+	p.IsType(&ast.ClassFieldCall{}, field.Class)
+	p.testIdentifier(field.Field, "c")
+
+	_, program = p.InitParserTests("-obj.campo;")
+	prefix := (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.Prefix)
+	p.IsType(&ast.ClassFieldCall{}, prefix.Rigth)
+
+	// a[i]:m() is grouped as (a[i]):m()
+	_, program = p.InitParserTests("a[i]:contiene(1);")
+	method := (program.Staments[0].(*ast.ExpressionStament)).Expression.(*ast.MethodExpression)
+	p.IsType(&ast.CallList{}, method.Obj)
+	p.IsType(&ast.Call{}, method.Method)
+}
+
+func (p *ParserTests) TestOperatorsRoundTrip() {
+	sources := []string{
+		"(a + b) * c;",
+		"a + b * c;",
+		"a - (b - c);",
+		"-(a + b) * -c;",
+		"!(a == b) || c && d;",
+		"a * b++ - c:longitud();",
+		"(a + b) / (c % 2) >= d.e;",
+	}
+
+	for _, source := range sources {
+		parser, program := p.InitParserTests(source)
+		p.testProgramStatements(parser, program, 1)
+
+		// parse -> format -> parse keeps the same grouping
+		formatted := program.Str()
+		reparsed, program := p.InitParserTests(formatted)
+		p.testProgramStatements(reparsed, program, 1)
+		p.Assert().Equal(formatted, program.Str(), source)
+	}
+
+	_, program := p.InitParserTests("(a + b) * c;")
+	p.Assert().Equal("((a + b) * c)", program.Str())
 }
 
 func (p *ParserTests) TestStringLiteral() {
@@ -509,6 +1005,29 @@ func (p *ParserTests) TestStringLiteral() {
 	p.Assert().Equal("hello world!", stringLiteral.Value)
 }
 
+func (p *ParserTests) TestInterpolatedString() {
+	source := `"hola ${nombre}, tienes ${edad + 1}";`
+
+	parser, program := p.InitParserTests(source)
+	p.testProgramStatements(parser, program, 1)
+	expressionStatement := program.Staments[0].(*ast.ExpressionStament)
+	if !p.Assert().IsType(&ast.InterpolatedString{}, expressionStatement.Expression) {
+		p.T().FailNow()
+	}
+
+	str := expressionStatement.Expression.(*ast.InterpolatedString)
+	p.Assert().Equal(4, len(str.Parts))
+	p.Assert().Equal("hola ", str.Parts[0].(*ast.StringLiteral).Value)
+	p.testIdentifier(str.Parts[1], "nombre")
+	p.Assert().Equal(", tienes ", str.Parts[2].(*ast.StringLiteral).Value)
+	p.testInfixExpression(str.Parts[3], "edad", "+", 1)
+
+	for _, source := range []string{`"${}";`, `"${1 2}";`, `"${1 + }";`} {
+		parser, _ := p.InitParserTests(source)
+		p.Assert().NotEmpty(parser.Errors())
+	}
+}
+
 func (p *ParserTests) testBoolean(expression ast.Expression, expectedValue bool) {
 	boolean := expression.(*ast.Boolean)
 	p.Assert().Equal(*boolean.Value, expectedValue)